@@ -186,6 +186,9 @@ func NewCommand() *cli.App {
 	noRecurseFlag := cli.BoolFlag{Name: "no_recurse", Usage: "Generate master model only.", Destination: &globalOpts.NoRecurse}
 	forceNewFlag := cli.BoolFlag{Name: "force", Aliases: []string{"f"}, Usage: "Force new a project, which will overwrite the generated files", Destination: &globalOpts.ForceNew}
 	forceUpdateClientFlag := cli.BoolFlag{Name: "force_client", Usage: "Force update 'crafter_client.go'", Destination: &globalOpts.ForceUpdateClient}
+	pruneFlag := cli.BoolFlag{Name: "prune", Usage: "Prune the clients whose IDL file is not found from the service group.", Destination: &globalOpts.Prune}
+	runtimeFlag := cli.StringFlag{Name: "runtime", Usage: "Specify the runtime the client is built on. (client-go or stdlib)", Value: meta.RuntimeClientGo, Destination: &globalOpts.Runtime}
+	genFakesFlag := cli.BoolFlag{Name: "gen_fakes", Usage: "Generate fake clients for unit tests in the 'fake' subpackage of the service group.", Destination: &globalOpts.GenFakes}
 
 	queryEnumIntFlag := cli.BoolFlag{Name: "query_enumint", Usage: "Use num instead of string for query enum parameter.", Destination: &globalOpts.QueryEnumAsInt}
 	unsetOmitemptyFlag := cli.BoolFlag{Name: "unset_omitempty", Usage: "Remove 'omitempty' tag for generated struct.", Destination: &globalOpts.UnsetOmitempty}
@@ -302,6 +305,9 @@ func NewCommand() *cli.App {
 				&clientDirFlag,
 				&forceClientDirFlag,
 				&forceUpdateClientFlag,
+				&genFakesFlag,
+				&runtimeFlag,
+				&pruneFlag,
 				&includesFlag,
 				&protoOptionsFlag,
 				&noRecurseFlag,
@@ -43,6 +43,7 @@ type Option struct {
 
 	JSONEnumStr          bool
 	QueryEnumAsInt       bool
+	Runtime              string
 	UnsetOmitempty       bool
 	ProtobufCamelJSONTag bool
 	ProtocOptions        []string // options to pass through to protoc
@@ -54,6 +55,8 @@ type Option struct {
 	HandlerByMethod      bool
 	ForceNew             bool
 	ForceUpdateClient    bool
+	GenFakes             bool
+	Prune                bool
 	SnakeStyleMiddleware bool
 	EnableExtends        bool
 	SortRouter           bool
@@ -95,6 +98,11 @@ func (opt *Option) Parse(c *cli.Context, cmd string) (*Option, error) {
 		return nil, err
 	}
 
+	err = option.checkRuntime()
+	if err != nil {
+		return nil, err
+	}
+
 	return option, nil
 }
 
@@ -161,6 +169,18 @@ func (opt *Option) checkIDL() error {
 	return nil
 }
 
+// checkRuntime defaults the client runtime to client-go and rejects unknown ones
+func (opt *Option) checkRuntime() error {
+	switch opt.Runtime {
+	case "":
+		opt.Runtime = meta.RuntimeClientGo
+	case meta.RuntimeClientGo, meta.RuntimeStdlib:
+	default:
+		return fmt.Errorf("runtime %s is not supported, it must be %s or %s", opt.Runtime, meta.RuntimeClientGo, meta.RuntimeStdlib)
+	}
+	return nil
+}
+
 func (opt *Option) IsUpdate() bool {
 	return opt.CmdType == meta.CmdUpdate
 }
@@ -1,26 +1,64 @@
 package generator
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/telecom-cloud/crafter/pkg/generator/model"
 	"github.com/telecom-cloud/crafter/pkg/meta"
 	tpl "github.com/telecom-cloud/crafter/pkg/template"
 	"github.com/telecom-cloud/crafter/pkg/util"
+	"github.com/telecom-cloud/crafter/pkg/util/logs"
 )
 
 const ServiceSuffix = "Service"
 
+// fakeDir is the subpackage of a service group holding the fake clients
+const fakeDir = "fake"
+
 type ClientMethod struct {
 	*HttpMethod
 	BodyParamsCode   string
 	QueryParamsCode  string
+	QueryStyleCode   string
 	PathParamsCode   string
 	HeaderParamsCode string
 	FormValueCode    string
 	FormFileCode     string
+	CookieParamsCode string
+	RawBodyCode      string
+	ContentType      string
+	ApiVersion       string
+	BaseUrl          string
 	DecodeCustomKey  string
+	EnvelopeCode     string
+	Idempotency      string // header of the idempotency key
+	FileFields       []*ClientFileField
+	Download         bool
+	Lro              *ClientLro
+}
+
+// ClientLro links a method starting a long-running operation to the method polling it
+type ClientLro struct {
+	Poll                string // name of the polling method
+	PollRequestTypeName string
+	PollReturnTypeName  string
+	JobIdGetter         string // getters of the job id on the response, such as GetJob().GetId()
+	JobIdZero           string // zero value of the job id, which means no job was started
+	PollJobId           string // field of the polling request set to the job id
+	PollJobRef          bool   // the field is a pointer
+	Status              string // getters of the status on the polling response
+	Success             []string
+	Failure             []string
+}
+
+// ClientFileField is a file_name field of a request, which can also be streamed from an io.Reader
+type ClientFileField struct {
+	Name   string // form field name
+	GoName string
 }
 
 type ClientConfig struct {
@@ -31,36 +69,63 @@ type ClientGenerator interface {
 }
 
 type ClientFile struct {
-	Config        ClientConfig
-	FilePath      string
-	PackageName   string
-	ServiceName   string
-	BaseDomain    string
-	Imports       map[string]*model.Model
-	ClientMethods []*ClientMethod
+	Config             ClientConfig
+	FilePath           string
+	PackageName        string
+	ServiceName        string
+	ServiceGroupImport string
+	GroupAlias         string // name the fakes import the service group by
+	BaseDomain         string
+	Imports            map[string]*model.Model
+	ClientMethods      []*ClientMethod
 }
 
 func (pkgGen *HttpPackageGenerator) genClient(pkg *PackageDescription, clientDir string) error {
 	module, _, _ := util.SearchGoMod(".", true)
 	baseDomain := pkgGen.BaseDomain
-	generatedJson := &meta.GeneratedJSON{}
+	var endpoints []string
+	regions := map[string]string{}
+	serviceJson := &meta.GeneratedJSON{
+		ServiceGroup: pkgGen.ServiceGroup,
+		Module:       module,
+		Clients:      []string{},
+		Files:        map[string][]string{},
+		Idl:          pkg.IdlName,
+	}
 	serviceGroupDir := filepath.Join(clientDir, pkgGen.ServiceGroup)
 	generatedJsonFile := filepath.Join(serviceGroupDir, "generated.json")
 	for _, s := range pkg.Services {
 		if baseDomain == "" {
 			baseDomain = s.BaseDomain
 		}
+		if len(endpoints) == 0 {
+			endpoints = s.Endpoints
+		}
+		for region, host := range s.Regions {
+			if _, ok := regions[region]; !ok {
+				regions[region] = host
+			}
+		}
+		// services hosted apart from the service group send their methods to their own domain
+		if s.BaseDomain != "" && s.BaseDomain != baseDomain {
+			for _, m := range s.ClientMethods {
+				if m.BaseUrl == "" {
+					m.BaseUrl = s.BaseDomain
+				}
+			}
+		}
 		cliDir := serviceGroupDir
 		if len(pkgGen.ForceClientDir) != 0 {
 			cliDir = pkgGen.ForceClientDir
 		}
 		idlClientFilePath := filepath.Join(cliDir, strings.ToLower(s.Name+".go"))
 		client := ClientFile{
-			FilePath:      idlClientFilePath,
-			PackageName:   pkgGen.ServiceGroup,
-			ServiceName:   util.ToCamelCase(s.Name),
-			ClientMethods: s.ClientMethods,
-			BaseDomain:    baseDomain,
+			FilePath:           idlClientFilePath,
+			PackageName:        pkgGen.ServiceGroup,
+			ServiceName:        util.ToCamelCase(s.Name),
+			ServiceGroupImport: util.SubPackage(pkgGen.ProjPackage, serviceGroupDir),
+			ClientMethods:      s.ClientMethods,
+			BaseDomain:         baseDomain,
 			Config: ClientConfig{
 				QueryEnumAsInt: pkgGen.QueryEnumAsInt,
 			},
@@ -89,29 +154,89 @@ func (pkgGen *HttpPackageGenerator) genClient(pkg *PackageDescription, clientDir
 		if err != nil {
 			return err
 		}
-		serviceJson := &meta.GeneratedJSON{
-			ServiceGroup: pkgGen.ServiceGroup,
-			Module:       module,
-			Clients: []string{
-				strings.TrimSuffix(s.Name, ServiceSuffix),
-			},
+		files := []string{client.FilePath}
+		if pkgGen.GenFakes {
+			client.GroupAlias = groupAlias(client.PackageName, client.Imports)
+			fakeFilePath := filepath.Join(serviceGroupDir, fakeDir, strings.ToLower(s.Name+".go"))
+			err = pkgGen.TemplateGenerator.Generate(client, tpl.IdlFakeClientTplName, fakeFilePath, false)
+			if err != nil {
+				return err
+			}
+			files = append(files, fakeFilePath)
 		}
-		generatedJson, err = meta.LoadGeneratedJson(generatedJsonFile, serviceJson)
-		if err != nil {
+		name := strings.TrimSuffix(s.Name, ServiceSuffix)
+		serviceJson.Clients = append(serviceJson.Clients, name)
+		for _, f := range files {
+			rel, err := filepath.Rel(serviceGroupDir, f)
+			if err != nil {
+				return err
+			}
+			serviceJson.Files[name] = append(serviceJson.Files[name], filepath.ToSlash(rel))
+		}
+	}
+
+	var keep func(client, source string) bool
+	if pkgGen.Prune {
+		keep = func(client, source string) bool {
+			if source == pkg.IdlName || source != "" && pkgGen.idlExists(source) {
+				return true
+			}
+			logs.Infof("prune client %s of %s from %s", client, source, generatedJsonFile)
+			return false
+		}
+	}
+	generatedJson, err := meta.LoadGeneratedJson(generatedJsonFile, serviceJson, keep)
+	if err != nil {
+		return err
+	}
+	// the files of the pruned clients refer to the types they were generated with, which may be gone
+	for _, f := range generatedJson.PrunedFiles() {
+		path := filepath.Join(serviceGroupDir, filepath.FromSlash(f))
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
 			return err
 		}
+		logs.Infof("remove %s of a pruned client", path)
 	}
 
-	return pkgGen.genServiceGroup(serviceGroupDir, baseDomain, generatedJson)
+	return pkgGen.genServiceGroup(serviceGroupDir, baseDomain, endpoints, regions, generatedJson)
 }
 
-func (pkgGen *HttpPackageGenerator) genServiceGroup(serviceGroupDir, baseDomain string, generatedJson *meta.GeneratedJSON) error {
-	return pkgGen.TemplateGenerator.Generate(map[string]interface{}{
+// groupAlias is the name of the service group import which does not collide with the model imports
+func groupAlias(group string, imports map[string]*model.Model) string {
+	alias := group
+	for imports[alias] != nil {
+		alias += "client"
+	}
+	return alias
+}
+
+// idlExists reports whether the IDL file source can still be found on the IDL search paths
+func (pkgGen *HttpPackageGenerator) idlExists(source string) bool {
+	for _, dir := range pkgGen.IdlIncludes {
+		if ok, _ := util.PathExist(filepath.Join(dir, source)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (pkgGen *HttpPackageGenerator) genServiceGroup(serviceGroupDir, baseDomain string, endpoints []string, regions map[string]string, generatedJson *meta.GeneratedJSON) error {
+	err := pkgGen.TemplateGenerator.Generate(map[string]interface{}{
 		"ServiceGroup": generatedJson.ServiceGroup,
 		"Module":       generatedJson.Module,
 		"BaseDomain":   baseDomain,
+		"Endpoints":    endpoints,
+		"Regions":      regions,
 		"Clients":      generatedJson.Clients,
 	}, tpl.IdlGroupClientTplName, filepath.Join(serviceGroupDir, strings.ToLower(pkgGen.ServiceGroup))+".go", false)
+	if err != nil || !pkgGen.GenFakes {
+		return err
+	}
+	return pkgGen.TemplateGenerator.Generate(map[string]interface{}{
+		"ServiceGroup":       generatedJson.ServiceGroup,
+		"ServiceGroupImport": util.SubPackage(pkgGen.ProjPackage, serviceGroupDir),
+		"Clients":            generatedJson.Clients,
+	}, tpl.IdlFakeGroupClientTplName, filepath.Join(serviceGroupDir, fakeDir, "clientset.go"), false)
 }
 
 func (pkgGen *HttpPackageGenerator) genHttpClient(clientDir, serviceGroupDir string) error {
@@ -120,17 +245,53 @@ func (pkgGen *HttpPackageGenerator) genHttpClient(clientDir, serviceGroupDir str
 	if err != nil {
 		return err
 	}
-	// generate http client once
+	// generate http client once, unless the clients generated now need another runtime
+	runtime := pkgGen.Runtime
+	if runtime == "" {
+		runtime = meta.RuntimeClientGo
+	}
+	version := fmt.Sprintf("%s/%d", runtime, meta.RuntimeRevision)
+	update := !isExist || pkgGen.ForceUpdateClient
+	if !update {
+		if current := runtimeVersionOf(httpClientPath); current != version {
+			logs.Infof("regenerate the runtime of %s, it is %s while the clients need %s", httpClientPath, current, version)
+			update = true
+		}
+	}
 
 	httpClient := map[string]interface{}{
 		"PackageName":    serviceGroupDir,
 		"QueryEnumAsInt": pkgGen.QueryEnumAsInt,
+		"Stdlib":         runtime == meta.RuntimeStdlib,
+		"RuntimeVersion": version,
 	}
-	if !isExist || pkgGen.ForceUpdateClient {
-		err := pkgGen.TemplateGenerator.Generate(httpClient, tpl.HttpClientTplName, httpClientPath, false)
+	// signer.go and recorder.go are part of the runtime, and are added to the clients generated before they existed
+	for _, name := range []string{tpl.HttpClientTplName, tpl.SignerTplName, tpl.RecorderTplName} {
+		path := filepath.Join(clientDir, serviceGroupDir, name)
+		isExist, err = util.PathExist(path)
 		if err != nil {
 			return err
 		}
+		if !isExist || update {
+			if err = pkgGen.TemplateGenerator.Generate(httpClient, name, path, false); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
+
+var runtimeVersionRegexp = regexp.MustCompile(`const runtimeVersion = "([^"]*)"`)
+
+// runtimeVersionOf returns the runtime version of the httpclient.go at path, which is empty for
+// the files generated before it was recorded
+func runtimeVersionOf(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	if m := runtimeVersionRegexp.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return "none"
+}
@@ -30,7 +30,9 @@ type Service struct {
 	DependencyModels []*model.Model
 	ServiceGroup     string
 	ServiceGenDir    string
-	BaseDomain       string // base domain for client code
+	BaseDomain       string            // base domain for client code
+	Endpoints        []string          // failover hosts for client code
+	Regions          map[string]string // hosts per region for client code
 }
 
 type HttpMethod struct {
@@ -48,6 +50,7 @@ type HttpMethod struct {
 	ReturnTypeName     string
 	ReturnTypePackage  string
 	ReturnTypeRawName  string
+	ErrorTypeName      string // message the client decodes the error body into
 	ModelPackage       map[string]string
 	GenHandler         bool // Whether to generate one handler, when an idl interface corresponds to multiple http method
 	// Annotations     map[string]string
@@ -70,11 +73,15 @@ type HttpPackageGenerator struct {
 	ForceClientDir string // client dir without namespace for "client" command
 	BaseDomain     string // request domain for "client" command
 	QueryEnumAsInt bool   // client code use number for query parameter
+	Runtime        string // runtime the client is built on, client-go or stdlib
 	ServiceGenDir  string
 
 	NeedModel            bool
 	SnakeStyleMiddleware bool // use snake name style for middleware
 	ForceUpdateClient    bool // force update 'crafter_client.go'
+	GenFakes             bool // generate fake clients for "client" command
+	Prune                bool // prune the clients of missing IDL files from generated.json for "client" command
+	IdlIncludes          []string
 
 	loadedBackend   ModelBackend
 	curModel        *model.Model
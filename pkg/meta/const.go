@@ -64,7 +64,42 @@ const (
 	BackendRust   Backend = "rust"
 )
 
+// Client runtimes
 const (
-	SetBodyParam      = "SetBodyParam(req).\n"
-	ContentTypeFormat = "\"Content-Type\": \"%s\","
+	RuntimeClientGo = "client-go" // the client is built on github.com/telecom-cloud/client-go
+	RuntimeStdlib   = "stdlib"    // the client is built on net/http only
 )
+
+// RuntimeRevision is the revision of the runtime generated in httpclient.go, signer.go and recorder.go.
+// Bump it whenever the generated clients use symbols an older runtime lacks, so that it is regenerated
+const RuntimeRevision = 1
+
+const (
+	SetBodyParam = "SetBodyParam(req).\n"
+)
+
+// Query styles of api.query_style
+const (
+	QueryStyleForm          = "form"            // repeated values are sent as a=1&a=2
+	QueryStyleFormNoExplode = "form_no_explode" // repeated values are sent as a=1,2
+	QueryStyleDot           = "dot"             // nested fields are sent as a.b=1
+	QueryStyleDeepObject    = "deep_object"     // nested fields are sent as a[b]=1
+	QueryStyleJSON          = "json"            // the value is sent as json
+)
+
+func IsQueryStyle(style string) bool {
+	switch style {
+	case QueryStyleForm, QueryStyleFormNoExplode, QueryStyleDot, QueryStyleDeepObject, QueryStyleJSON:
+		return true
+	}
+	return false
+}
+
+// SerializerContentTypes maps the values of api.serializer to the content type used by the client
+var SerializerContentTypes = map[string]string{
+	"json":     "application/json; charset=utf-8",
+	"form":     "application/x-www-form-urlencoded",
+	"xml":      "application/xml; charset=utf-8",
+	"protobuf": "application/x-protobuf",
+	"text":     "text/plain; charset=utf-8",
+}
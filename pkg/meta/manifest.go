@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	gv "github.com/hashicorp/go-version"
 	"gopkg.in/yaml.v3"
@@ -79,32 +80,54 @@ func loadConfigFile(path string) (*Manifest, error) {
 }
 
 type GeneratedJSON struct {
-	ServiceGroup string   `json:"serviceGroup"`
-	Module       string   `json:"module"`
-	Clients      []string `json:"clients"`
+	ServiceGroup string            `json:"serviceGroup"`
+	Module       string            `json:"module"`
+	Clients      []string          `json:"clients"`
+	Sources      map[string]string `json:"sources,omitempty"` // the IDL file each client is declared in
+	// Files are the files generated for each client, relative to the service group directory
+	Files map[string][]string `json:"files,omitempty"`
+	Idl   string              `json:"-"` // the IDL file the clients are generated from
+
+	pruned []string
 }
 
-func LoadGeneratedJson(filename string, generatedJson *GeneratedJSON) (*GeneratedJSON, error) {
-	// Check if the file exists
-	_, err := os.Stat(filename)
-	if os.IsNotExist(err) {
-		// File does not exist, create it
-		file, err := os.Create(filename)
-		if err != nil {
-			return nil, err
+// PrunedFiles are the files of the clients pruned by LoadGeneratedJson which no client left generates,
+// they are unknown for the clients recorded before their files were
+func (g *GeneratedJSON) PrunedFiles() []string {
+	kept := make(map[string]bool)
+	for _, files := range g.Files {
+		for _, f := range files {
+			kept[f] = true
 		}
-		defer file.Close()
-		err = writeJsonToFile(filename, generatedJson)
-		if err != nil {
-			return nil, err
+	}
+	var pruned []string
+	for _, f := range g.pruned {
+		if !kept[f] {
+			pruned = append(pruned, f)
 		}
-		return generatedJson, nil
+	}
+	return pruned
+}
+
+// LoadGeneratedJson merges generatedJson into the registry at filename and writes the result back.
+// The clients of generatedJson replace those recorded for its IDL file, so removed services are pruned.
+// When keep is not nil, the clients it rejects by their name and source are pruned as well,
+// the source is empty for the clients recorded before sources were.
+func LoadGeneratedJson(filename string, generatedJson *GeneratedJSON, keep func(client, source string) bool) (*GeneratedJSON, error) {
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return nil, err
 	}
 	original, err := readJsonFromFile(filename)
+	if os.IsNotExist(err) {
+		original, err = &GeneratedJSON{ServiceGroup: generatedJson.ServiceGroup, Module: generatedJson.Module}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 	merged := merge(original, generatedJson)
+	if keep != nil {
+		merged.prune(keep)
+	}
 	err = writeJsonToFile(filename, merged)
 	if err != nil {
 		return nil, err
@@ -122,7 +145,6 @@ func writeJsonToFile(filename string, data *GeneratedJSON) error {
 }
 
 func readJsonFromFile(filename string) (*GeneratedJSON, error) {
-	// Open the file in append mode
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
@@ -130,27 +152,56 @@ func readJsonFromFile(filename string) (*GeneratedJSON, error) {
 	var original *GeneratedJSON
 	err = json.Unmarshal(data, &original)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("read %s failed: %v", filename, err)
 	}
 
 	return original, nil
 }
 
 func merge(original, generated *GeneratedJSON) *GeneratedJSON {
-	indexFn := func(list []string) map[string]string {
-		listMap := make(map[string]string)
-		for _, item := range list {
-			listMap[item] = item
-		}
-		return listMap
+	if original.Sources == nil {
+		original.Sources = make(map[string]string, len(generated.Clients))
+	}
+	if original.Files == nil {
+		original.Files = make(map[string][]string, len(generated.Clients))
+	}
+	if generated.Idl != "" {
+		original.prune(func(client, source string) bool {
+			return source != generated.Idl
+		})
 	}
 
-	indexed := indexFn(original.Clients)
+	indexed := make(map[string]bool, len(original.Clients))
+	for _, client := range original.Clients {
+		indexed[client] = true
+	}
 	for _, client := range generated.Clients {
-		if _, ok := indexed[client]; !ok {
+		if !indexed[client] {
+			indexed[client] = true
 			original.Clients = append(original.Clients, client)
 		}
+		if generated.Idl != "" {
+			original.Sources[client] = generated.Idl
+		}
+		if files, ok := generated.Files[client]; ok {
+			original.Files[client] = files
+		}
 	}
+	sort.Strings(original.Clients)
 
 	return original
 }
+
+func (g *GeneratedJSON) prune(keep func(client, source string) bool) {
+	clients := g.Clients[:0]
+	for _, client := range g.Clients {
+		if keep(client, g.Sources[client]) {
+			clients = append(clients, client)
+			continue
+		}
+		delete(g.Sources, client)
+		g.pruned = append(g.pruned, g.Files[client]...)
+		delete(g.Files, client)
+	}
+	g.Clients = clients
+}
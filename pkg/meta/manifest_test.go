@@ -2,6 +2,8 @@ package meta
 
 import (
 	"fmt"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	gv "github.com/hashicorp/go-version"
@@ -24,10 +26,78 @@ func TestGeneratedJson(t *testing.T) {
 		},
 	}
 	filename := "generated.json"
-	data, err := LoadGeneratedJson(filename, original)
+	data, err := LoadGeneratedJson(filename, original, nil)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 	fmt.Println(data)
 }
+
+func TestGeneratedJsonPrune(t *testing.T) {
+	// the service group dir does not exist before the first client is generated
+	filename := filepath.Join(t.TempDir(), "eci", "generated.json")
+	load := func(idl string, keep func(client, source string) bool, clients ...string) *GeneratedJSON {
+		data, err := LoadGeneratedJson(filename, &GeneratedJSON{ServiceGroup: "eci", Clients: clients, Idl: idl}, keep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	expect := func(data *GeneratedJSON, clients ...string) {
+		if !reflect.DeepEqual(data.Clients, clients) {
+			t.Fatalf("clients %v, want %v", data.Clients, clients)
+		}
+	}
+
+	load("", nil, "Legacy")
+	expect(load("b.proto", nil, "Volume", "Disk"), "Disk", "Legacy", "Volume")
+	data := load("a.proto", nil, "Region")
+	expect(data, "Disk", "Legacy", "Region", "Volume")
+	if data.Sources["Disk"] != "b.proto" || data.Sources["Region"] != "a.proto" || data.Sources["Legacy"] != "" {
+		t.Fatalf("sources %v", data.Sources)
+	}
+
+	// Volume is removed from b.proto, Disk is renamed
+	expect(load("b.proto", nil, "Snapshot", "Disk2"), "Disk2", "Legacy", "Region", "Snapshot")
+	// a.proto declares no service any more
+	expect(load("a.proto", nil), "Disk2", "Legacy", "Snapshot")
+
+	data = load("c.proto", func(client, source string) bool {
+		return source != ""
+	}, "Vpc")
+	expect(data, "Disk2", "Snapshot", "Vpc")
+	if _, ok := data.Sources["Legacy"]; ok {
+		t.Fatalf("sources %v", data.Sources)
+	}
+}
+
+func TestGeneratedJsonPrunedFiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "eci", "generated.json")
+	load := func(idl string, files map[string][]string, keep func(client, source string) bool) []string {
+		data := &GeneratedJSON{ServiceGroup: "eci", Files: files, Idl: idl}
+		for client := range files {
+			data.Clients = append(data.Clients, client)
+		}
+		data, err := LoadGeneratedJson(filename, data, keep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data.PrunedFiles()
+	}
+
+	load("", map[string][]string{"Legacy": nil}, nil)
+	load("a.proto", map[string][]string{"Disk": {"disk.go", "fake/disk.go"}, "Volume": {"volume.go"}}, nil)
+	// Disk is renamed to the file it was generated to before, Volume is removed
+	pruned := load("a.proto", map[string][]string{"DiskV2": {"disk.go"}}, nil)
+	if want := []string{"fake/disk.go", "volume.go"}; !reflect.DeepEqual(pruned, want) {
+		t.Fatalf("pruned %v, want %v", pruned, want)
+	}
+	// the files of Legacy are not recorded
+	pruned = load("a.proto", map[string][]string{"DiskV2": {"disk.go"}}, func(client, source string) bool {
+		return source != ""
+	})
+	if len(pruned) != 0 {
+		t.Fatalf("pruned %v", pruned)
+	}
+}
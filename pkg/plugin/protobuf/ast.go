@@ -2,8 +2,11 @@ package protobuf
 
 import (
 	"fmt"
+	"net/http"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/jhump/protoreflect/desc"
@@ -109,11 +112,19 @@ func astToService(ast *descriptorpb.FileDescriptorProto, resolver *Resolver, cmd
 			if ok && len(val) != 0 {
 				service.BaseDomain = val
 			}
+			if err := parseServiceEndpoints(service, s); err != nil {
+				return nil, err
+			}
+		}
+		serviceEnvelope, err := parseEnvelope(checkFirstOption(api.E_ServiceEnvelope, s.GetOptions()), "service "+s.GetName())
+		if err != nil {
+			return nil, err
 		}
 
 		ms := s.GetMethod()
 		methods := make([]*generator.HttpMethod, 0, len(ms))
 		clientMethods := make([]*generator.ClientMethod, 0, len(ms))
+		clientNames := make(map[string]string, len(ms))
 		servicePathAnno := checkFirstOption(api.E_ServicePath, s.GetOptions())
 		servicePath := ""
 		if val, ok := servicePathAnno.(string); ok {
@@ -175,6 +186,17 @@ func astToService(ast *descriptorpb.FileDescriptorProto, resolver *Resolver, cmd
 			respRawName := outputGoType.GoIdent.GoName
 			respName = respPackage + "." + respRawName
 
+			var (
+				errSymbol *Symbol
+				errGoName string
+			)
+			if cmdType == meta.CmdClient {
+				errSymbol, errGoName, err = resolveErrorType(resolver, gen, ast, m)
+				if err != nil {
+					return nil, err
+				}
+			}
+
 			var serializer string
 			sl, sv := checkFirstOptions(SerializerOptions, m.GetOptions())
 			if sl != "" {
@@ -224,8 +246,17 @@ func astToService(ast *descriptorpb.FileDescriptorProto, resolver *Resolver, cmd
 			method.ReturnTypeName = respName
 			method.ReturnTypeRawName = respRawName
 			method.ReturnTypePackage = respPackage
+			if errSymbol != nil {
+				errPackage := errSymbol.Scope.GetOptions().GetGoPackage()
+				errAlias := goOptMapAlias[errPackage]
+				if errAlias == "" {
+					errAlias = util.BaseName(errPackage, "")
+				}
+				method.ErrorTypeName = errAlias + "." + errGoName
+			}
 
 			methods = append(methods, method)
+			bindings := []*generator.HttpMethod{method}
 			for idx, anno := range httpOpts {
 				if idx == 0 {
 					continue
@@ -235,6 +266,7 @@ func astToService(ast *descriptorpb.FileDescriptorProto, resolver *Resolver, cmd
 				tmp.Path = anno.path
 				tmp.GenHandler = false
 				methods = append(methods, &tmp)
+				bindings = append(bindings, &tmp)
 			}
 
 			if cmdType == meta.CmdClient {
@@ -244,10 +276,36 @@ func astToService(ast *descriptorpb.FileDescriptorProto, resolver *Resolver, cmd
 				if err != nil {
 					return nil, err
 				}
+				if clientMethod.EnvelopeCode == "" {
+					clientMethod.EnvelopeCode = serviceEnvelope
+				}
+				if err = claimClientName(clientNames, clientMethod.Name, m.GetName()); err != nil {
+					return nil, err
+				}
 				clientMethods = append(clientMethods, clientMethod)
+				// the first binding is sent by GetFoo, the others get one method each, such as GetFooByPost
+				for _, binding := range bindings[1:] {
+					clientMethod := &generator.ClientMethod{}
+					clientMethod.HttpMethod = binding
+					err := parseAnnotationToClient(clientMethod, gen, ast, m)
+					if err != nil {
+						return nil, err
+					}
+					if clientMethod.EnvelopeCode == "" {
+						clientMethod.EnvelopeCode = serviceEnvelope
+					}
+					clientMethod.Name = binding.Name + "By" + util.ToHttpMethod(binding.HTTPMethod)
+					if err = claimClientName(clientNames, clientMethod.Name, m.GetName()); err != nil {
+						return nil, err
+					}
+					clientMethods = append(clientMethods, clientMethod)
+				}
 			}
 		}
 
+		if err := linkLro(clientMethods); err != nil {
+			return nil, err
+		}
 		service.ClientMethods = clientMethods
 		service.Methods = methods
 		service.DependencyModels = merges
@@ -256,6 +314,275 @@ func astToService(ast *descriptorpb.FileDescriptorProto, resolver *Resolver, cmd
 	return out, nil
 }
 
+// claimClientName records name as the client method of rpc, the extra binding GetFooByPost of GetFoo
+// collides with an rpc named GetFooByPost
+func claimClientName(names map[string]string, name, rpc string) error {
+	if other, ok := names[name]; ok {
+		return fmt.Errorf("client method %s of rpc %s collides with the one of rpc %s, rename one of the rpcs", name, rpc, other)
+	}
+	names[name] = rpc
+	return nil
+}
+
+// parseServiceEndpoints reads the failover endpoints and the region hosts of a service
+func parseServiceEndpoints(service *generator.Service, s *descriptorpb.ServiceDescriptorProto) error {
+	if val, ok := checkFirstOption(api.E_Endpoints, s.GetOptions()).(string); ok {
+		for _, endpoint := range strings.Split(val, ",") {
+			if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+				service.Endpoints = append(service.Endpoints, endpoint)
+			}
+		}
+	}
+	if val, ok := checkFirstOption(api.E_Regions, s.GetOptions()).(string); ok {
+		for _, entry := range strings.Split(val, ",") {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			region, host, found := strings.Cut(entry, "=")
+			region, host = strings.TrimSpace(region), strings.TrimSpace(host)
+			if !found || region == "" || host == "" {
+				return fmt.Errorf("invalid region \"%s\" of service %s, it must be region=host", entry, s.GetName())
+			}
+			if service.Regions == nil {
+				service.Regions = make(map[string]string)
+			}
+			service.Regions[region] = host
+		}
+	}
+	return nil
+}
+
+// resolveErrorType resolves the message named by the error_type annotation of m and its Go name,
+// a name without a leading dot is looked up in the package of ast first
+func resolveErrorType(resolver *Resolver, gen *protogen.Plugin, ast *descriptorpb.FileDescriptorProto, m *descriptorpb.MethodDescriptorProto) (*Symbol, string, error) {
+	name, ok := checkFirstOption(api.E_ErrorType, m.GetOptions()).(string)
+	if !ok || strings.TrimSpace(name) == "" {
+		return nil, "", nil
+	}
+	name = strings.TrimSpace(name)
+	ids := []string{name}
+	if !strings.HasPrefix(name, ".") {
+		ids = []string{"." + ast.GetPackage() + "." + name, "." + name}
+	}
+	for _, id := range ids {
+		if resolver.Get(id) == nil {
+			continue
+		}
+		symbol, err := resolver.ResolveIdentifier(id)
+		if err != nil {
+			return nil, "", err
+		}
+		if file, exist := gen.FilesByPath[symbol.Scope.GetName()]; exist {
+			if message := findMessage(file.Messages, strings.TrimPrefix(id, ".")); message != nil {
+				return symbol, message.GoIdent.GoName, nil
+			}
+		}
+		return nil, "", fmt.Errorf("error type %s of method %s must be a message", name, m.GetName())
+	}
+	return nil, "", fmt.Errorf("not found error type %s of method %s", name, m.GetName())
+}
+
+// findMessage looks up the message of the full name among messages and their nested messages
+func findMessage(messages []*protogen.Message, fullName string) *protogen.Message {
+	for _, message := range messages {
+		if string(message.Desc.FullName()) == fullName {
+			return message
+		}
+		if nested := findMessage(message.Messages, fullName); nested != nil {
+			return nested
+		}
+	}
+	return nil
+}
+
+// envelopeFields are the keys of the envelope annotation and the Envelope fields of the client they set
+var envelopeFields = [][2]string{
+	{"code", "CodeField"},
+	{"success", "SuccessCode"},
+	{"data", "DataPath"},
+	{"reason", "ReasonField"},
+	{"message", "MessageField"},
+}
+
+// parseEnvelope turns the envelope annotation of owner into the fields of the client Envelope,
+// it is empty when the annotation is not set
+func parseEnvelope(anno interface{}, owner string) (string, error) {
+	val, ok := anno.(string)
+	if val = strings.TrimSpace(val); !ok || val == "" {
+		return "", nil
+	}
+	if val == "none" {
+		return "None: true", nil
+	}
+	keys := make([]string, 0, len(envelopeFields))
+	for _, f := range envelopeFields {
+		keys = append(keys, f[0])
+	}
+	values, bad := parseKeyValues(val, keys)
+	if bad != "" {
+		return "", fmt.Errorf("invalid envelope \"%s\" of %s, it must be none or key=value of code, success, data, reason and message", bad, owner)
+	}
+	if (values["code"] == "") != (values["success"] == "") {
+		return "", fmt.Errorf("envelope of %s must set code and success together", owner)
+	}
+	fields := make([]string, 0, len(values))
+	for _, f := range envelopeFields {
+		if value, ok := values[f[0]]; ok {
+			fields = append(fields, fmt.Sprintf("%s: %q", f[1], value))
+		}
+	}
+	return strings.Join(fields, ", "), nil
+}
+
+// parseKeyValues parses the key=value entries of an annotation separated by commas,
+// bad is the first entry which is malformed or has a key out of keys
+func parseKeyValues(val string, keys []string) (values map[string]string, bad string) {
+	values = make(map[string]string, len(keys))
+	for _, entry := range strings.Split(val, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		key, value, found := strings.Cut(entry, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !found || value == "" || !slices.Contains(keys, key) {
+			return nil, entry
+		}
+		values[key] = value
+	}
+	return values, ""
+}
+
+// lroKeys are the keys of the lro annotation
+var lroKeys = []string{"poll", "job_id", "poll_job_id", "status", "success", "failure"}
+
+// parseLro links method to the method of its service polling the operation it starts, the types of
+// the polling method are filled in by linkLro once all the methods of the service are parsed
+func parseLro(clientMethod *generator.ClientMethod, method *protogen.Method) error {
+	val, ok := proto.GetExtension(method.Desc.Options(), api.E_Lro).(string)
+	if val = strings.TrimSpace(val); !ok || val == "" {
+		return nil
+	}
+	values, bad := parseKeyValues(val, lroKeys)
+	if bad != "" {
+		return fmt.Errorf("invalid lro \"%s\" of method %s, it must be key=value of %s", bad, clientMethod.Name, strings.Join(lroKeys, ", "))
+	}
+	for _, key := range []string{"poll", "job_id", "status", "success"} {
+		if values[key] == "" {
+			return fmt.Errorf("lro of method %s must set %s", clientMethod.Name, key)
+		}
+	}
+	var poll *protogen.Method
+	for _, m := range method.Parent.Methods {
+		if string(m.Desc.Name()) == values["poll"] {
+			poll = m
+		}
+	}
+	if poll == nil {
+		return fmt.Errorf("not found poll method %s of method %s in service %s", values["poll"], clientMethod.Name, method.Parent.Desc.Name())
+	}
+	jobId, jobIdField, err := fieldGetters(method.Output, values["job_id"])
+	if err != nil {
+		return fmt.Errorf("invalid job_id of method %s: %v", clientMethod.Name, err)
+	}
+	pollJobId := values["poll_job_id"]
+	if pollJobId == "" {
+		pollJobId = values["job_id"]
+	}
+	pollJobIdField := findField(poll.Input, pollJobId)
+	if pollJobIdField == nil {
+		return fmt.Errorf("not found poll_job_id %s of method %s in %s", pollJobId, clientMethod.Name, poll.Input.Desc.Name())
+	}
+	if jobIdField.Message != nil || pollJobIdField.Desc.Kind() != jobIdField.Desc.Kind() || pollJobIdField.Desc.IsList() || jobIdField.Desc.IsList() {
+		return fmt.Errorf("job_id %s and poll_job_id %s of method %s must be fields of the same type", values["job_id"], pollJobId, clientMethod.Name)
+	}
+	status, _, err := fieldGetters(poll.Output, values["status"])
+	if err != nil {
+		return fmt.Errorf("invalid status of method %s: %v", clientMethod.Name, err)
+	}
+	jobIdZero := "0"
+	switch jobIdField.Desc.Kind() {
+	case protoreflect.StringKind:
+		jobIdZero = `""`
+	case protoreflect.BoolKind:
+		jobIdZero = "false"
+	case protoreflect.BytesKind:
+		jobIdZero = "nil"
+	}
+	clientMethod.Lro = &generator.ClientLro{
+		Poll:        util.CamelString(string(poll.Desc.Name())),
+		JobIdGetter: jobId,
+		JobIdZero:   jobIdZero,
+		PollJobId:   pollJobIdField.GoName,
+		PollJobRef:  pollJobIdField.Desc.HasPresence(),
+		Status:      status,
+		Success:     splitTerminals(values["success"]),
+		Failure:     splitTerminals(values["failure"]),
+	}
+	return nil
+}
+
+// linkLro fills in the request and response types of the polling methods of methods
+func linkLro(methods []*generator.ClientMethod) error {
+	byName := make(map[string]*generator.ClientMethod, len(methods))
+	for _, m := range methods {
+		byName[m.Name] = m
+	}
+	for _, m := range methods {
+		if m.Lro == nil {
+			continue
+		}
+		poll := byName[m.Lro.Poll]
+		if poll == nil {
+			return fmt.Errorf("poll method %s of method %s has no client method, it needs an http annotation such as api.get", m.Lro.Poll, m.Name)
+		}
+		m.Lro.PollRequestTypeName = poll.RequestTypeName
+		m.Lro.PollReturnTypeName = poll.ReturnTypeName
+	}
+	return nil
+}
+
+// fieldGetters turns a dot separated path of fields of message into a chain of getters,
+// every field but the last one must be a message
+func fieldGetters(message *protogen.Message, path string) (string, *protogen.Field, error) {
+	var (
+		getters []string
+		field   *protogen.Field
+	)
+	for i, name := range strings.Split(path, ".") {
+		if i > 0 {
+			if field.Message == nil || field.Desc.IsList() || field.Desc.IsMap() {
+				return "", nil, fmt.Errorf("field %s of %s is not a message", field.Desc.Name(), path)
+			}
+			message = field.Message
+		}
+		if field = findField(message, name); field == nil {
+			return "", nil, fmt.Errorf("not found field %s of %s in %s", name, path, message.Desc.Name())
+		}
+		getters = append(getters, "Get"+field.GoName+"()")
+	}
+	return strings.Join(getters, "."), field, nil
+}
+
+// findField looks up the field of message by its proto name or Go name
+func findField(message *protogen.Message, name string) *protogen.Field {
+	for _, f := range message.Fields {
+		if string(f.Desc.Name()) == name || f.GoName == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func splitTerminals(val string) []string {
+	var terminals []string
+	for _, t := range strings.Split(val, "|") {
+		if t = strings.TrimSpace(t); t != "" {
+			terminals = append(terminals, t)
+		}
+	}
+	return terminals
+}
+
 func getCompatibleAnnotation(options proto.Message, anno, compatibleAnno *protoimpl.ExtensionInfo) interface{} {
 	if proto.HasExtension(options, anno) {
 		return checkFirstOption(anno, options)
@@ -287,16 +614,21 @@ func parseAnnotationToClient(clientMethod *generator.ClientMethod, gen *protogen
 		if f.Desc.Kind() == protoreflect.StringKind {
 			isStringFieldType = true
 		}
+		if proto.HasExtension(f.Desc.Options(), api.E_QueryStyle) {
+			style := proto.GetExtension(f.Desc.Options(), api.E_QueryStyle).(string)
+			if !meta.IsQueryStyle(style) {
+				return fmt.Errorf("unsupported query_style \"%s\" of field %s.%s", style, inputType.Desc.Name(), f.Desc.Name())
+			}
+			clientMethod.QueryStyleCode += fmt.Sprintf("%q: %q,\n", queryParamName(f), style)
+		}
 		if proto.HasExtension(f.Desc.Options(), api.E_Query) {
 			hasAnnotation = true
-			queryAnnos := proto.GetExtension(f.Desc.Options(), api.E_Query)
-			val := checkSnakeName(queryAnnos.(string))
+			val := queryParamName(f)
 			clientMethod.QueryParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
 		}
 		if proto.HasExtension(f.Desc.Options(), api.E_QueryCompatible) {
 			hasAnnotation = true
-			queryAnnos := proto.GetExtension(f.Desc.Options(), api.E_QueryCompatible)
-			val := checkSnakeName(queryAnnos.(string))
+			val := queryParamName(f)
 			clientMethod.QueryParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
 			if isStringFieldType {
 				clientMethod.HeaderParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
@@ -348,35 +680,134 @@ func parseAnnotationToClient(clientMethod *generator.ClientMethod, gen *protogen
 			hasFormAnnotation = true
 			val := fileAnnos.(string)
 			clientMethod.FormFileCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
+			clientMethod.FileFields = append(clientMethod.FileFields, &generator.ClientFileField{Name: val, GoName: f.GoName})
 		}
 		if proto.HasExtension(f.Desc.Options(), api.E_Cookie) {
 			hasAnnotation = true
-			// cookie do nothing
+			cookieAnnos := proto.GetExtension(f.Desc.Options(), api.E_Cookie)
+			val := cookieAnnos.(string)
+			if isStringFieldType {
+				clientMethod.CookieParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", val, f.GoName)
+			} else {
+				clientMethod.CookieParamsCode += fmt.Sprintf("%q: fmt.Sprint(req.Get%s()),\n", val, f.GoName)
+			}
+		}
+
+		if proto.HasExtension(f.Desc.Options(), api.E_RawBody) {
+			hasAnnotation = true
+			switch f.Desc.Kind() {
+			case protoreflect.BytesKind:
+				clientMethod.RawBodyCode = fmt.Sprintf("SetRawBody(req.Get%s()).\n", f.GoName)
+			case protoreflect.StringKind:
+				clientMethod.RawBodyCode = fmt.Sprintf("SetRawBody([]byte(req.Get%s())).\n", f.GoName)
+			default:
+				return fmt.Errorf("raw_body field %s.%s must be string or bytes", inputType.Desc.Name(), f.Desc.Name())
+			}
 		}
 		if !hasAnnotation && strings.EqualFold(clientMethod.HTTPMethod, "get") {
-			clientMethod.QueryParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", checkSnakeName(string(f.Desc.Name())), f.GoName)
+			clientMethod.QueryParamsCode += fmt.Sprintf("%q: req.Get%s(),\n", queryParamName(f), f.GoName)
 		}
 	}
 	clientMethod.BodyParamsCode = meta.SetBodyParam
 	if hasBodyAnnotation && hasFormAnnotation {
 		clientMethod.FormValueCode = ""
 		clientMethod.FormFileCode = ""
+		clientMethod.FileFields = nil
 	}
 	if !hasBodyAnnotation && hasFormAnnotation {
 		clientMethod.BodyParamsCode = ""
 	}
+	// raw_body replaces the serialized request body
+	if clientMethod.RawBodyCode != "" {
+		clientMethod.BodyParamsCode = clientMethod.RawBodyCode
+	}
 
+	// content_type takes precedence over the content type implied by serializer
 	if proto.HasExtension(method.Desc.Options(), api.E_ContentType) {
-		clientMethod.HeaderParamsCode = fmt.Sprintf(meta.ContentTypeFormat, proto.GetExtension(method.Desc.Options(), api.E_ContentType))
+		clientMethod.ContentType = proto.GetExtension(method.Desc.Options(), api.E_ContentType).(string)
+	} else if serializer := clientMethod.Serializer; serializer != "" {
+		contentType, ok := meta.SerializerContentTypes[strings.ToLower(serializer)]
+		if !ok {
+			logs.Warnf("unsupported serializer \"%s\" of method %s, the body is sent as json", serializer, clientMethod.Name)
+		}
+		clientMethod.ContentType = contentType
+	}
+
+	// client routes are resolved on a copy, so handlers keep the annotated path,
+	// gen_path replaces the route before its :version is resolved
+	httpMethod := *clientMethod.HttpMethod
+	clientMethod.HttpMethod = &httpMethod
+	genPath, _ := checkFirstOption(api.E_GenPath, method.Desc.Options()).(string)
+	if genPath != "" {
+		clientMethod.Path = genPath
+	}
+	if apiVersion, ok := checkFirstOption(api.E_ApiVersion, method.Desc.Options()).(string); ok && apiVersion != "" {
+		clientMethod.ApiVersion = apiVersion
+		clientMethod.Path = replacePathVariable(clientMethod.Path, "version", apiVersion)
+	} else if replacePathVariable(genPath, "version", "") != genPath {
+		return fmt.Errorf("gen_path \"%s\" of method %s has :version, but the method sets no api_version", genPath, clientMethod.Name)
+	}
+	if baseUrl, ok := checkFirstOption(api.E_Baseurl, method.Desc.Options()).(string); ok && baseUrl != "" {
+		clientMethod.BaseUrl = baseUrl
+	}
+
+	if proto.HasExtension(method.Desc.Options(), api.E_Download) {
+		download := proto.GetExtension(method.Desc.Options(), api.E_Download).(string)
+		clientMethod.Download, err = strconv.ParseBool(download)
+		if err != nil {
+			return fmt.Errorf("invalid download \"%s\" of method %s, it must be a bool", download, clientMethod.Name)
+		}
 	}
 
 	if proto.HasExtension(method.Desc.Options(), api.E_DecodeCustomKey) {
 		clientMethod.DecodeCustomKey = fmt.Sprintf("%s", proto.GetExtension(method.Desc.Options(), api.E_DecodeCustomKey))
 	}
 
+	if proto.HasExtension(method.Desc.Options(), api.E_Idempotency) {
+		idempotency := strings.TrimSpace(proto.GetExtension(method.Desc.Options(), api.E_Idempotency).(string))
+		switch idempotency {
+		case "", "false":
+		case "true":
+			clientMethod.Idempotency = "Idempotency-Key"
+		default:
+			clientMethod.Idempotency = http.CanonicalHeaderKey(idempotency)
+		}
+	}
+
+	if err = parseLro(clientMethod, method); err != nil {
+		return err
+	}
+
+	clientMethod.EnvelopeCode, err = parseEnvelope(checkFirstOption(api.E_Envelope, m.GetOptions()), "method "+clientMethod.Name)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// queryParamName returns the name of the query parameter bound to f
+func queryParamName(f *protogen.Field) string {
+	if proto.HasExtension(f.Desc.Options(), api.E_Query) {
+		return checkSnakeName(proto.GetExtension(f.Desc.Options(), api.E_Query).(string))
+	}
+	if proto.HasExtension(f.Desc.Options(), api.E_QueryCompatible) {
+		return checkSnakeName(proto.GetExtension(f.Desc.Options(), api.E_QueryCompatible).(string))
+	}
+	return checkSnakeName(string(f.Desc.Name()))
+}
+
+// replacePathVariable replaces the path segments ":name" with value
+func replacePathVariable(path, name, value string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == ":"+name {
+			segments[i] = value
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
 func getMethod(file *protogen.File, m *descriptorpb.MethodDescriptorProto) (*protogen.Method, error) {
 	for _, f := range file.Services {
 		for _, method := range f.Methods {
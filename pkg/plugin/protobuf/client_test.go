@@ -0,0 +1,700 @@
+package protobuf
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/telecom-cloud/crafter/cmd/cft/app/options"
+	"github.com/telecom-cloud/crafter/pkg/meta"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// generateClient runs the client command on the idl of test_data/client, the project is generated
+// into a new directory with the stdlib runtime and the service group "demo" unless set changes them
+func generateClient(t *testing.T, idl string, set func(opt *options.Option)) (string, error) {
+	t.Helper()
+	dir, _, err := generateClientWarns(t, idl, set)
+	return dir, err
+}
+
+// generateClientWarns is generateClient returning the warnings of the generation as well
+func generateClientWarns(t *testing.T, idl string, set func(opt *options.Option)) (string, string, error) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := protoparse.Parser{
+		ImportPaths:           []string{filepath.Join(wd, "test_data", "client"), filepath.Join(wd, "api")},
+		IncludeSourceCodeInfo: true,
+	}
+	fds, err := parser.ParseFiles(idl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := t.TempDir()
+	opt := options.NewOption()
+	opt.CmdType = meta.CmdClient
+	opt.ServiceGroup = "demo"
+	opt.Gomod = "example.com/demo"
+	opt.ClientDir = "client"
+	opt.ModelDir = "model"
+	opt.OutDir = out
+	opt.Cwd = out
+	opt.Runtime = meta.RuntimeStdlib
+	if set != nil {
+		set(opt)
+	}
+	out = opt.OutDir
+	writeGoMod(t, out)
+	params, err := opt.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{fds[0].GetName()},
+		ProtoFile:      protoFiles(fds[0], map[string]bool{}),
+		Parameter:      proto.String(strings.Join(params, ",")),
+	}
+
+	// the plugin writes its response to stdout and resolves the project from the working directory
+	respFile, err := os.Create(filepath.Join(t.TempDir(), "response"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = respFile
+	defer func() {
+		os.Stdout = stdout
+		os.Chdir(wd)
+	}()
+	if err = os.Chdir(out); err != nil {
+		t.Fatal(err)
+	}
+	plu := &Plugin{}
+	plu.setLogger()
+	args, err := plu.parseArgs(req.GetParameter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	CheckTagOption(args)
+	if err = plu.Handle(req, args); err != nil {
+		return "", "", err
+	}
+	respFile.Close()
+	warns := plu.logger.Warn()
+
+	data, err := os.ReadFile(respFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := &pluginpb.CodeGeneratorResponse{}
+	if err = proto.Unmarshal(data, resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != nil {
+		return "", warns, errors.New(resp.GetError())
+	}
+	for _, f := range resp.File {
+		// the model files are named by their go package, which is rooted at the module
+		name := f.GetName()
+		if !strings.HasPrefix(name, out) {
+			name = filepath.Join(out, name)
+		}
+		if err = os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(name, []byte(f.GetContent()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return out, warns, nil
+}
+
+func protoFiles(fd *desc.FileDescriptor, seen map[string]bool) []*descriptorpb.FileDescriptorProto {
+	if seen[fd.GetName()] {
+		return nil
+	}
+	seen[fd.GetName()] = true
+	var files []*descriptorpb.FileDescriptorProto
+	for _, dep := range fd.GetDependencies() {
+		files = append(files, protoFiles(dep, seen)...)
+	}
+	return append(files, fd.AsFileDescriptorProto())
+}
+
+// writeGoMod makes dir a module requiring the protobuf runtime crafter is built with
+func writeGoMod(t *testing.T, dir string) {
+	t.Helper()
+	version, err := exec.Command("go", "list", "-m", "-f", "{{.Version}}", "google.golang.org/protobuf").Output()
+	if err != nil {
+		t.Skipf("go toolchain is not available: %v", err)
+	}
+	goMod := "module example.com/demo\n\ngo 1.22\n\nrequire google.golang.org/protobuf " + strings.TrimSpace(string(version)) + "\n"
+	if err = os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join("..", "..", "..", "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "go.sum"), goSum, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// vetProject builds and vets the generated project in dir
+func vetProject(t *testing.T, dir string) {
+	t.Helper()
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated project does not build: %v\n%s", err, out)
+	}
+}
+
+// testProject runs the test file src in the client package of the generated project in dir
+func testProject(t *testing.T, dir, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "client", "demo", "demo_test.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "test", "./client/demo/")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated client test failed: %v\n%s", err, out)
+	}
+}
+
+func readGenerated(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "client", "demo", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestClientGenPathVersion(t *testing.T) {
+	dir, err := generateClient(t, "demo.proto", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := readGenerated(t, dir, "itemservice.go")
+	if !strings.Contains(client, `"/v2/items/:id"`) {
+		t.Fatalf("the :version of gen_path is not resolved:\n%s", client)
+	}
+	vetProject(t, dir)
+}
+
+func TestClientGenPathWithoutVersion(t *testing.T) {
+	_, err := generateClient(t, "gen_path_no_version.proto", nil)
+	if err == nil || !strings.Contains(err.Error(), `gen_path "/:version/items/:id" of method GetItem has :version, but the method sets no api_version`) {
+		t.Fatalf("error %v", err)
+	}
+}
+
+func TestClientBindings(t *testing.T) {
+	dir, err := generateClient(t, "demo.proto", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := readGenerated(t, dir, "itemservice.go")
+	for _, method := range []string{"ListItems(", "ListItemsByPost(", "TouchItem("} {
+		if !strings.Contains(client, ") "+method) {
+			t.Fatalf("method %s is not generated", method)
+		}
+	}
+	if strings.Contains(client, "ListItemsByGet") {
+		t.Fatal("the first binding is generated twice")
+	}
+	testProject(t, dir, `package demo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	model "example.com/demo/model/demo"
+)
+
+func TestBindings(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`+"`"+`{"statusCode":800,"returnObj":{}}`+"`"+`))
+	}))
+	defer srv.Close()
+	c, err := NewItemClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	c.ListItems(ctx, &model.ListItemsReq{})
+	c.ListItemsByPost(ctx, &model.ListItemsReq{})
+	c.TouchItem(ctx, &model.GetItemReq{Id: "1"})
+	c.TouchItem(ctx, &model.GetItemReq{Id: "1"}, WithHttpMethod("put"))
+	want := []string{"GET /items", "POST /items/search", "POST /items/1/touch", "PUT /items/1/touch"}
+	if len(got) != len(want) {
+		t.Fatalf("requests %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("requests %v, want %v", got, want)
+		}
+	}
+}
+`)
+}
+
+func TestClientBindingConflict(t *testing.T) {
+	_, err := generateClient(t, "binding_conflict.proto", nil)
+	if err == nil || !strings.Contains(err.Error(), "client method ListItemsByPost of rpc ListItemsByPost collides with the one of rpc ListItems") {
+		t.Fatalf("error %v", err)
+	}
+}
+
+func TestClientSerializer(t *testing.T) {
+	dir, warns, err := generateClientWarns(t, "serializer.proto", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(warns, `unsupported serializer "yaml" of method CreateYamlItem, the body is sent as json`) {
+		t.Fatalf("warnings %q", warns)
+	}
+	testProject(t, dir, `package demo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	model "example.com/demo/model/demo"
+)
+
+func TestSerializer(t *testing.T) {
+	got := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got[r.URL.Path] = r.Header.Get("Content-Type") + " " + string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`+"`"+`{"statusCode":800,"returnObj":{}}`+"`"+`))
+	}))
+	defer srv.Close()
+	c, err := NewItemClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, _, err = c.CreateXmlItem(ctx, &model.CreateItemReq{Name: "n"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = c.CreateYamlItem(ctx, &model.CreateItemReq{Name: "n"}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"/xml":  "application/xml; charset=utf-8 <CreateItemReq><Name>n</Name></CreateItemReq>",
+		"/yaml": `+"`"+`application/json; charset=utf-8 {"name":"n"}`+"`"+`,
+	}
+	for path, req := range want {
+		if got[path] != req {
+			t.Fatalf("%s sent %q, want %q", path, got[path], req)
+		}
+	}
+}
+`)
+}
+
+func TestClientBaseUrl(t *testing.T) {
+	dir, err := generateClient(t, "baseurl.proto", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"demo.go":        `var baseDomain = "http://items.example"`,
+		"itemservice.go": `SetHostUrl("http://legacy.example")`,
+		"jobservice.go":  `SetHostUrl("http://jobs.example")`,
+	} {
+		if !strings.Contains(readGenerated(t, dir, name), want) {
+			t.Fatalf("%s does not contain %s", name, want)
+		}
+	}
+	testProject(t, dir, `package demo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	model "example.com/demo/model/demo"
+)
+
+// hosts answers every request without sending it and tells the urls the requests were sent to
+func hosts() (Option, *[]string) {
+	urls := &[]string{}
+	return WithClient(DoerFunc(func(req *http.Request) (*http.Response, error) {
+		*urls = append(*urls, req.URL.String())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`+"`"+`{"statusCode":800,"returnObj":{}}`+"`"+`)),
+			Request:    req,
+		}, nil
+	})), urls
+}
+
+func TestBaseUrl(t *testing.T) {
+	ctx := context.Background()
+	req := &model.GetReq{Id: "1"}
+	send := func(options ...Option) string {
+		doer, urls := hosts()
+		options = append(options, doer)
+		cs, err := NewClientSet(baseDomain, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cs.Item().GetItem(ctx, req)
+		cs.Item().GetLegacyItem(ctx, req)
+		cs.Job().GetJob(ctx, req)
+		if err = ConfigDefaultItemClient(options...); err != nil {
+			t.Fatal(err)
+		}
+		GetItem(ctx, req)
+		return strings.Join(*urls, " ")
+	}
+
+	got := send()
+	if want := "http://items.example/items/1 http://legacy.example/legacy/1 http://jobs.example/jobs/1 http://items.example/items/1"; got != want {
+		t.Fatalf("sent to %s, want %s", got, want)
+	}
+	// the host of WithHostUrl takes precedence over the base urls of the IDL
+	got = send(WithHostUrl("http://override.example"))
+	if want := "http://override.example/items/1 http://override.example/legacy/1 http://override.example/jobs/1 http://override.example/items/1"; got != want {
+		t.Fatalf("sent to %s, want %s", got, want)
+	}
+}
+`)
+}
+
+func TestClientSet(t *testing.T) {
+	dir, err := generateClient(t, "clientset.proto", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testProject(t, dir, `package demo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	model "example.com/demo/model/demo"
+)
+
+func TestClientSet(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	doer := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		sent = append(sent, req.URL.Path+" "+req.Header.Get("X-Service"))
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`+"`"+`{"statusCode":800,"returnObj":{}}`+"`"+`)),
+			Request:    req,
+		}, nil
+	})
+	set, err := NewClientSet(baseDomain, WithClient(doer),
+		WithServiceOption("Job", WithHeader(http.Header{"X-Service": {"job"}})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := set.(*clientSet)
+
+	// the clients are created on first use, once whoever asks for them
+	if cs.itemCli != nil || cs.jobCli != nil {
+		t.Fatal("the clients are created along with the set")
+	}
+	clients := make([]ItemClient, 8)
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients[i] = cs.Item()
+		}(i)
+	}
+	wg.Wait()
+	for _, c := range clients {
+		if c != clients[0] {
+			t.Fatal("the item client is created more than once")
+		}
+	}
+	if cs.jobCli != nil {
+		t.Fatal("the job client is created along with the item client")
+	}
+
+	// both clients send through the doer of the set, only the job client carries its service options
+	ctx := context.Background()
+	if _, _, err = cs.Item().GetItem(ctx, &model.GetReq{Id: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = cs.Job().GetJob(ctx, &model.GetReq{Id: "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(sent, ","); got != "/items/1 ,/jobs/2 job" {
+		t.Fatalf("sent %s", got)
+	}
+	if cs.httpClientOf("Item") != cs.httpClient {
+		t.Fatal("the item client does not share the client of the set")
+	}
+
+	if _, err = NewClientSet(baseDomain, WithServiceOption("Unknown")); err == nil || !strings.Contains(err.Error(), "unknown service Unknown") {
+		t.Fatalf("error %v", err)
+	}
+}
+`)
+}
+
+func TestClientCookieAndRawBody(t *testing.T) {
+	dir, err := generateClient(t, "cookie_raw_body.proto", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testProject(t, dir, `package demo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	model "example.com/demo/model/demo"
+)
+
+func TestCookieAndRawBody(t *testing.T) {
+	got := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got[r.URL.Path] = r.Header.Get("Content-Type") + " " + string(body)
+		got[r.URL.Path+" cookie"] = r.Header.Get("Cookie")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`+"`"+`{"statusCode":800,"returnObj":{}}`+"`"+`))
+	}))
+	defer srv.Close()
+	c, err := NewBlobClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	_, _, err = c.PutBlob(ctx, &model.PutBlobReq{Session: "s-1", Tenant: 7, Data: []byte{0xff, 0x00, 'b'}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the raw body is sent as it is, not as a json string
+	if _, _, err = c.PutText(ctx, &model.PutTextReq{Text: `+"`"+`{"a":1}`+"`"+`}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"/blobs":        "application/octet-stream \xff\x00b",
+		"/blobs cookie": "session=s-1; tenant=7",
+		"/texts":        `+"`"+`text/plain; charset=utf-8 {"a":1}`+"`"+`,
+		"/texts cookie": "",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("%s sent %q, want %q", k, got[k], v)
+		}
+	}
+}
+`)
+}
+
+func TestClientStaleRuntime(t *testing.T) {
+	dir, err := generateClient(t, "demo.proto", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an httpclient.go generated by an older cft records no runtime version
+	path := filepath.Join(dir, "client", "demo", "httpclient.go")
+	runtime := readGenerated(t, dir, "httpclient.go")
+	stale := regexp.MustCompile(`const runtimeVersion = "[^"]*"\n`).ReplaceAllString(runtime, "")
+	if stale == runtime {
+		t.Fatal("the runtime version is not recorded")
+	}
+	if err = os.WriteFile(path, []byte(stale), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	set := func(opt *options.Option) {
+		opt.OutDir = dir
+		opt.Cwd = dir
+	}
+	if _, err = generateClient(t, "demo.proto", set); err != nil {
+		t.Fatal(err)
+	}
+	if readGenerated(t, dir, "httpclient.go") != runtime {
+		t.Fatal("the stale runtime is not regenerated")
+	}
+	vetProject(t, dir)
+
+	// a current runtime is kept as it is
+	edited := runtime + "\n// edited\n"
+	if err = os.WriteFile(path, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = generateClient(t, "demo.proto", set); err != nil {
+		t.Fatal(err)
+	}
+	if readGenerated(t, dir, "httpclient.go") != edited {
+		t.Fatal("the current runtime is regenerated")
+	}
+}
+
+func TestClientLro(t *testing.T) {
+	dir, err := generateClient(t, "lro.proto", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testProject(t, dir, `package demo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	model "example.com/demo/model/demo"
+)
+
+func TestStartJobAndWait(t *testing.T) {
+	var jobId string
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			fmt.Fprintf(w, "{\"statusCode\":800,\"returnObj\":{\"job_id\":%q}}", jobId)
+			return
+		}
+		polls++
+		if polls == 2 {
+			// a transient failure of the poll is retried
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, "<html>unavailable</html>")
+			return
+		}
+		status := "running"
+		if polls == 3 {
+			status = "done"
+		}
+		fmt.Fprintf(w, "{\"statusCode\":800,\"returnObj\":{\"job_id\":%q,\"status\":%q}}", strings.TrimPrefix(r.URL.Path, "/jobs/"), status)
+	}))
+	defer srv.Close()
+	c, err := NewJobClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &WaitOptions{Interval: time.Millisecond}
+
+	jobId = "job-1"
+	job, err := c.StartJobAndWait(context.Background(), &model.StartJobReq{}, opts)
+	if err != nil || job.GetJobId() != "job-1" || job.GetStatus() != "done" || polls != 3 {
+		t.Fatalf("job %v, error %v, polls %d", job, err, polls)
+	}
+
+	// a job which is not started is not polled
+	jobId, polls = "", 0
+	if _, err = c.StartJobAndWait(context.Background(), &model.StartJobReq{}, opts); err == nil || polls != 0 {
+		t.Fatalf("error %v, polls %d", err, polls)
+	}
+}
+`)
+}
+
+func TestClientLroPollWithoutHttp(t *testing.T) {
+	_, err := generateClient(t, "lro_no_http.proto", nil)
+	if err == nil || !strings.Contains(err.Error(), "poll method GetJob of method StartJob has no client method") {
+		t.Fatalf("error %v", err)
+	}
+}
+
+func TestClientFakes(t *testing.T) {
+	// the service group and the models share the package name demo
+	dir, err := generateClient(t, "demo.proto", func(opt *options.Option) {
+		opt.GenFakes = true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vetProject(t, dir)
+}
+
+func TestClientPrune(t *testing.T) {
+	dir, err := generateClient(t, "demo.proto", func(opt *options.Option) {
+		opt.GenFakes = true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// demo.proto is not in the include paths of the second generation, so its clients are pruned
+	_, err = generateClient(t, "cookie_raw_body.proto", func(opt *options.Option) {
+		opt.OutDir = dir
+		opt.Cwd = dir
+		opt.GenFakes = true
+		opt.Prune = true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"itemservice.go", filepath.Join("fake", "itemservice.go")} {
+		if _, err = os.Stat(filepath.Join(dir, "client", "demo", name)); !os.IsNotExist(err) {
+			t.Fatalf("%s of the pruned client is not removed: %v", name, err)
+		}
+	}
+	readGenerated(t, dir, "blobservice.go")
+	vetProject(t, dir)
+}
+
+func TestClientValidate(t *testing.T) {
+	for cmd, want := range map[string]bool{meta.CmdClient: true, meta.CmdModel: false} {
+		dir, err := generateClient(t, "demo.proto", func(opt *options.Option) {
+			opt.CmdType = cmd
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		model, err := os.ReadFile(filepath.Join(dir, "model", "demo", "demo.pb.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(string(model), "func (x *GetItemReq) Validate() error"); got != want {
+			t.Fatalf("%s generates Validate: %v, want %v", cmd, got, want)
+		}
+	}
+}
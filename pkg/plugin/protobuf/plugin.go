@@ -36,6 +36,8 @@ type Plugin struct {
 	RmTags       RemoveTags
 	PkgMap       map[string]string
 	logger       *logs.StdLogger
+	// Validate generates Validate for the messages, which only the generated clients call
+	Validate bool
 }
 
 type RemoveTags []string
@@ -169,6 +171,7 @@ func (plugin *Plugin) Handle(req *pluginpb.CodeGeneratorRequest, args *options.O
 	gen, err := opts.New(req)
 	plugin.Plugin = gen
 	plugin.RmTags = args.RmTags
+	plugin.Validate = args.CmdType == meta.CmdClient
 	if err != nil {
 		return fmt.Errorf("new protoc plugin failed: %s", err.Error())
 	}
@@ -393,6 +396,9 @@ func (plugin *Plugin) generateFile(gen *protogen.Plugin, file *protogen.File, rm
 		if err != nil {
 			return nil, err
 		}
+		if plugin.Validate {
+			genMessageValidate(g, message)
+		}
 	}
 	genExtensions(g, f)
 
@@ -590,8 +596,12 @@ func (plugin *Plugin) generatePackageFiles(ast *descriptorpb.FileDescriptorProto
 		ForceClientDir:       args.ForceClientDir,
 		BaseDomain:           args.BaseDomain,
 		QueryEnumAsInt:       args.QueryEnumAsInt,
+		Runtime:              args.Runtime,
 		SnakeStyleMiddleware: args.SnakeStyleMiddleware,
 		ForceUpdateClient:    args.ForceUpdateClient,
+		GenFakes:             args.GenFakes,
+		Prune:                args.Prune,
+		IdlIncludes:          idlIncludes(args),
 	}
 
 	if args.ModelBackend != "" {
@@ -611,3 +621,18 @@ func (plugin *Plugin) generatePackageFiles(ast *descriptorpb.FileDescriptorProto
 
 	return files, nil
 }
+
+// idlIncludes returns the paths protoc searches the IDL files in
+func idlIncludes(args *options.Option) []string {
+	includes := make([]string, 0, len(args.Includes)+len(args.IdlPaths))
+	for _, inc := range args.Includes {
+		if !filepath.IsAbs(inc) {
+			inc = filepath.Join(args.Cwd, inc)
+		}
+		includes = append(includes, inc)
+	}
+	for _, path := range args.IdlPaths {
+		includes = append(includes, filepath.Dir(path))
+	}
+	return includes
+}
@@ -0,0 +1,623 @@
+package protobuf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/telecom-cloud/crafter/pkg/plugin/protobuf/api"
+	"github.com/telecom-cloud/crafter/pkg/util/logs"
+)
+
+// vdKind is the Go type of a translated vd operand
+type vdKind int
+
+const (
+	vdInvalid vdKind = iota
+	vdNumber         // numbers are converted to float64, so fields of any width compare
+	vdString
+	vdBool
+	vdNil
+	vdList // repeated and map fields
+	vdMessage
+)
+
+func (k vdKind) String() string {
+	return [...]string{"invalid", "number", "string", "bool", "nil", "list", "message"}[k]
+}
+
+// vdOperand is the Go code of a vd sub expression
+type vdOperand struct {
+	code string
+	kind vdKind
+}
+
+// vdEnv resolves what a vd expression refers to
+type vdEnv struct {
+	self    vdOperand                           // $
+	resolve func(path string) (vdOperand, bool) // (Field) and (Field.Sub)
+	ident   func(importPath, name string) string
+	regexp  func(pattern string) string // returns a compiled *regexp.Regexp for pattern
+}
+
+// vdRule is a vd annotation split into its expression and error message
+type vdRule struct {
+	expr string
+	msg  string
+}
+
+// parseVdRule splits the "expr; msg:'...'" form of a vd annotation
+func parseVdRule(vd string) (vdRule, error) {
+	var rule vdRule
+	for _, part := range splitVd(vd) {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case strings.HasPrefix(part, "msg:"):
+			msg := strings.TrimSpace(strings.TrimPrefix(part, "msg:"))
+			toks, err := tokenizeVd(msg)
+			if err != nil || len(toks) != 1 || toks[0].kind != vdTokString {
+				return rule, fmt.Errorf("only literal msg is supported: %s", msg)
+			}
+			rule.msg = toks[0].text
+		case strings.HasPrefix(part, "@:"):
+			rule.expr = strings.TrimSpace(part[2:])
+		case rule.expr == "":
+			rule.expr = part
+		default:
+			return rule, fmt.Errorf("unsupported vd part: %s", part)
+		}
+	}
+	if rule.expr == "" {
+		return rule, fmt.Errorf("empty vd expression")
+	}
+	return rule, nil
+}
+
+// splitVd splits vd on the semicolons outside of string literals
+func splitVd(vd string) []string {
+	var parts []string
+	inString, start := false, 0
+	for i := 0; i < len(vd); i++ {
+		switch vd[i] {
+		case '\\':
+			i++
+		case '\'':
+			inString = !inString
+		case ';':
+			if !inString {
+				parts = append(parts, vd[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, vd[start:])
+}
+
+type vdTokenKind int
+
+const (
+	vdTokNumber vdTokenKind = iota
+	vdTokString
+	vdTokIdent
+	vdTokSelf
+	vdTokField
+	vdTokOp
+)
+
+type vdToken struct {
+	kind vdTokenKind
+	text string
+}
+
+var vdOperators = []string{"==", "!=", ">=", "<=", "&&", "||", ">", "<", "!", "+", "-", "*", "/", "%", "(", ")", ","}
+
+func tokenizeVd(expr string) ([]vdToken, error) {
+	var toks []vdToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '$':
+			toks = append(toks, vdToken{vdTokSelf, "$"})
+			i++
+		case c == '\'':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(expr) && expr[j] != '\''; j++ {
+				// only quotes are escaped, so regular expressions keep their backslashes
+				if expr[j] == '\\' && j+1 < len(expr) && expr[j+1] == '\'' {
+					j++
+				}
+				sb.WriteByte(expr[j])
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated string in %s", expr)
+			}
+			toks = append(toks, vdToken{vdTokString, sb.String()})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(expr) && (expr[j] >= '0' && expr[j] <= '9' || expr[j] == '.') {
+				j++
+			}
+			if _, err := strconv.ParseFloat(expr[i:j], 64); err != nil {
+				return nil, fmt.Errorf("invalid number %s", expr[i:j])
+			}
+			toks = append(toks, vdToken{vdTokNumber, expr[i:j]})
+			i = j
+		case isVdIdentByte(c, true):
+			j := i
+			for j < len(expr) && isVdIdentByte(expr[j], false) {
+				j++
+			}
+			toks = append(toks, vdToken{vdTokIdent, expr[i:j]})
+			i = j
+		case c == '(' && (len(toks) == 0 || toks[len(toks)-1].kind != vdTokIdent):
+			// (Field) selects a field of the message, unless the parenthesis opens a call
+			if j := strings.IndexByte(expr[i:], ')'); j > 1 && isVdFieldPath(expr[i+1:i+j]) {
+				toks = append(toks, vdToken{vdTokField, expr[i+1 : i+j]})
+				i += j + 1
+				continue
+			}
+			toks = append(toks, vdToken{vdTokOp, "("})
+			i++
+		default:
+			op := ""
+			for _, o := range vdOperators {
+				if strings.HasPrefix(expr[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q in %s", c, expr)
+			}
+			toks = append(toks, vdToken{vdTokOp, op})
+			i += len(op)
+		}
+	}
+	return toks, nil
+}
+
+func isVdIdentByte(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
+
+func isVdFieldPath(s string) bool {
+	for _, p := range strings.Split(s, ".") {
+		if p == "" || !isVdIdentByte(p[0], true) {
+			return false
+		}
+		for i := range p {
+			if !isVdIdentByte(p[i], false) {
+				return false
+			}
+		}
+	}
+	return s != "true" && s != "false" && s != "nil"
+}
+
+// translateVd translates a vd expression to a Go boolean expression
+func translateVd(expr string, env *vdEnv) (string, error) {
+	toks, err := tokenizeVd(expr)
+	if err != nil {
+		return "", err
+	}
+	p := &vdParser{toks: toks, env: env}
+	op, err := p.parseOr()
+	if err != nil {
+		return "", err
+	}
+	if p.pos != len(p.toks) {
+		return "", fmt.Errorf("unexpected %s in %s", p.toks[p.pos].text, expr)
+	}
+	if op.kind != vdBool {
+		return "", fmt.Errorf("%s is not a bool expression", expr)
+	}
+	return op.code, nil
+}
+
+type vdParser struct {
+	toks []vdToken
+	pos  int
+	env  *vdEnv
+}
+
+func (p *vdParser) peekOp(ops ...string) string {
+	if p.pos >= len(p.toks) || p.toks[p.pos].kind != vdTokOp {
+		return ""
+	}
+	for _, op := range ops {
+		if p.toks[p.pos].text == op {
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *vdParser) expectOp(op string) error {
+	if p.peekOp(op) == "" {
+		return fmt.Errorf("expected %s", op)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *vdParser) parseOr() (vdOperand, error) {
+	return p.parseLogical("||", p.parseAnd)
+}
+
+func (p *vdParser) parseAnd() (vdOperand, error) {
+	return p.parseLogical("&&", p.parseCompare)
+}
+
+func (p *vdParser) parseLogical(op string, next func() (vdOperand, error)) (vdOperand, error) {
+	left, err := next()
+	if err != nil {
+		return left, err
+	}
+	for p.peekOp(op) != "" {
+		p.pos++
+		right, err := next()
+		if err != nil {
+			return right, err
+		}
+		if left.kind != vdBool || right.kind != vdBool {
+			return left, fmt.Errorf("%s needs bool operands", op)
+		}
+		left = vdOperand{fmt.Sprintf("(%s %s %s)", left.code, op, right.code), vdBool}
+	}
+	return left, nil
+}
+
+func (p *vdParser) parseCompare() (vdOperand, error) {
+	left, err := p.parseAdd()
+	if err != nil {
+		return left, err
+	}
+	op := p.peekOp("==", "!=", ">=", "<=", ">", "<")
+	if op == "" {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.parseAdd()
+	if err != nil {
+		return right, err
+	}
+	return compareVd(op, left, right)
+}
+
+func compareVd(op string, left, right vdOperand) (vdOperand, error) {
+	ok := false
+	switch {
+	case left.kind == vdNil || right.kind == vdNil:
+		other := left.kind
+		if other == vdNil {
+			other = right.kind
+		}
+		ok = (op == "==" || op == "!=") && (other == vdList || other == vdMessage)
+	case left.kind != right.kind:
+	case op == "==" || op == "!=":
+		ok = left.kind == vdNumber || left.kind == vdString || left.kind == vdBool
+	default:
+		ok = left.kind == vdNumber || left.kind == vdString
+	}
+	if !ok {
+		return vdOperand{}, fmt.Errorf("can not compare %s %s %s", left.kind, op, right.kind)
+	}
+	return vdOperand{fmt.Sprintf("(%s %s %s)", left.code, op, right.code), vdBool}, nil
+}
+
+func (p *vdParser) parseAdd() (vdOperand, error) {
+	return p.parseArithmetic([]string{"+", "-"}, p.parseMul)
+}
+
+func (p *vdParser) parseMul() (vdOperand, error) {
+	return p.parseArithmetic([]string{"*", "/", "%"}, p.parseUnary)
+}
+
+func (p *vdParser) parseArithmetic(ops []string, next func() (vdOperand, error)) (vdOperand, error) {
+	left, err := next()
+	if err != nil {
+		return left, err
+	}
+	for op := p.peekOp(ops...); op != ""; op = p.peekOp(ops...) {
+		p.pos++
+		right, err := next()
+		if err != nil {
+			return right, err
+		}
+		switch {
+		case left.kind == vdNumber && right.kind == vdNumber && op == "%":
+			left = vdOperand{fmt.Sprintf("%s(%s, %s)", p.env.ident("math", "Mod"), left.code, right.code), vdNumber}
+		case left.kind == vdNumber && right.kind == vdNumber,
+			left.kind == vdString && right.kind == vdString && op == "+":
+			left = vdOperand{fmt.Sprintf("(%s %s %s)", left.code, op, right.code), left.kind}
+		default:
+			return left, fmt.Errorf("can not compute %s %s %s", left.kind, op, right.kind)
+		}
+	}
+	return left, nil
+}
+
+func (p *vdParser) parseUnary() (vdOperand, error) {
+	switch p.peekOp("!", "-") {
+	case "!":
+		p.pos++
+		op, err := p.parseUnary()
+		if err != nil || op.kind != vdBool {
+			return op, fmt.Errorf("! needs a bool operand")
+		}
+		return vdOperand{"!" + op.code, vdBool}, nil
+	case "-":
+		p.pos++
+		op, err := p.parseUnary()
+		if err != nil || op.kind != vdNumber {
+			return op, fmt.Errorf("- needs a number operand")
+		}
+		return vdOperand{"-" + op.code, vdNumber}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *vdParser) parsePrimary() (vdOperand, error) {
+	if p.pos >= len(p.toks) {
+		return vdOperand{}, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.toks[p.pos]
+	p.pos++
+	switch tok.kind {
+	case vdTokNumber:
+		return vdOperand{tok.text, vdNumber}, nil
+	case vdTokString:
+		return vdOperand{strconv.Quote(tok.text), vdString}, nil
+	case vdTokSelf:
+		return p.env.self, nil
+	case vdTokField:
+		op, ok := p.env.resolve(tok.text)
+		if !ok {
+			return op, fmt.Errorf("unknown field %s", tok.text)
+		}
+		return op, nil
+	case vdTokOp:
+		if tok.text != "(" {
+			return vdOperand{}, fmt.Errorf("unexpected %s", tok.text)
+		}
+		op, err := p.parseOr()
+		if err != nil {
+			return op, err
+		}
+		return op, p.expectOp(")")
+	}
+
+	switch tok.text {
+	case "true", "false":
+		return vdOperand{tok.text, vdBool}, nil
+	case "nil":
+		return vdOperand{"nil", vdNil}, nil
+	case "regexp":
+		return p.parseRegexp()
+	}
+	args, err := p.parseArgs()
+	if err != nil {
+		return vdOperand{}, err
+	}
+	switch {
+	case tok.text == "len" && len(args) == 1 && (args[0].kind == vdString || args[0].kind == vdList):
+		return vdOperand{fmt.Sprintf("float64(len(%s))", args[0].code), vdNumber}, nil
+	case tok.text == "mblen" && len(args) == 1 && args[0].kind == vdString:
+		return vdOperand{fmt.Sprintf("float64(%s(%s))", p.env.ident("unicode/utf8", "RuneCountInString"), args[0].code), vdNumber}, nil
+	case tok.text == "in" && len(args) > 1:
+		conds := make([]string, 0, len(args)-1)
+		for _, arg := range args[1:] {
+			cond, err := compareVd("==", args[0], arg)
+			if err != nil {
+				return cond, err
+			}
+			conds = append(conds, cond.code)
+		}
+		return vdOperand{"(" + strings.Join(conds, " || ") + ")", vdBool}, nil
+	}
+	return vdOperand{}, fmt.Errorf("unsupported function %s with %d arguments", tok.text, len(args))
+}
+
+func (p *vdParser) parseArgs() ([]vdOperand, error) {
+	if err := p.expectOp("("); err != nil {
+		return nil, err
+	}
+	var args []vdOperand
+	for p.peekOp(")") == "" {
+		if len(args) > 0 {
+			if err := p.expectOp(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.pos++
+	return args, nil
+}
+
+// parseRegexp parses regexp('pattern') matching $, or regexp('pattern', target)
+func (p *vdParser) parseRegexp() (vdOperand, error) {
+	if err := p.expectOp("("); err != nil {
+		return vdOperand{}, err
+	}
+	if p.pos >= len(p.toks) || p.toks[p.pos].kind != vdTokString {
+		return vdOperand{}, fmt.Errorf("regexp needs a literal pattern")
+	}
+	pattern := p.toks[p.pos].text
+	p.pos++
+	target := p.env.self
+	if p.peekOp(",") != "" {
+		p.pos++
+		var err error
+		if target, err = p.parseOr(); err != nil {
+			return target, err
+		}
+	}
+	if err := p.expectOp(")"); err != nil {
+		return vdOperand{}, err
+	}
+	if target.kind != vdString {
+		return vdOperand{}, fmt.Errorf("regexp matches strings only")
+	}
+	return vdOperand{fmt.Sprintf("%s.MatchString(%s)", p.env.regexp(pattern), target.code), vdBool}, nil
+}
+
+// vdFieldOperand is the value of field read from the message in code
+func vdFieldOperand(code string, field *protogen.Field) vdOperand {
+	getter := code + ".Get" + field.GoName + "()"
+	if field.Desc.IsList() || field.Desc.IsMap() {
+		return vdOperand{getter, vdList}
+	}
+	switch field.Desc.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return vdOperand{getter, vdMessage}
+	case protoreflect.StringKind:
+		return vdOperand{getter, vdString}
+	case protoreflect.BytesKind:
+		return vdOperand{"string(" + getter + ")", vdString}
+	case protoreflect.BoolKind:
+		return vdOperand{getter, vdBool}
+	default:
+		return vdOperand{"float64(" + getter + ")", vdNumber}
+	}
+}
+
+func findVdField(m *protogen.Message, name string) *protogen.Field {
+	for _, f := range m.Fields {
+		if f.GoName == name || string(f.Desc.Name()) == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// needsValidate reports whether m or one of its message fields has api.vd expressions
+func needsValidate(m *protogen.Message, visited map[*protogen.Message]bool) bool {
+	if visited[m] {
+		return false
+	}
+	visited[m] = true
+	for _, f := range m.Fields {
+		if checkFirstOption(api.E_Vd, f.Desc.Options()) != nil {
+			return true
+		}
+		if f.Message != nil && !f.Desc.IsMap() && needsValidate(f.Message, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// genMessageValidate generates Validate checking the api.vd expressions of m,
+// generated clients call it before sending a request
+func genMessageValidate(g *protogen.GeneratedFile, m *messageInfo) {
+	if m.Desc.IsMapEntry() || !needsValidate(m.Message, map[*protogen.Message]bool{}) {
+		return
+	}
+	ident := func(importPath, name string) string {
+		return g.QualifiedGoIdent(protogen.GoIdent{GoName: name, GoImportPath: protogen.GoImportPath(importPath)})
+	}
+	var regexps []string
+	g.P("// Validate checks the api.vd expressions of ", m.GoIdent.GoName, " and of its message fields")
+	g.P("func (x *", m.GoIdent, ") Validate() error {")
+	g.P("if x == nil {")
+	g.P("return nil")
+	g.P("}")
+	for _, field := range m.Fields {
+		vd, ok := checkFirstOption(api.E_Vd, field.Desc.Options()).(string)
+		if !ok {
+			continue
+		}
+		name := fmt.Sprintf("%s.%s", m.Desc.Name(), field.Desc.Name())
+		rule, err := parseVdRule(vd)
+		if err == nil {
+			env := &vdEnv{
+				self: vdFieldOperand("x", field),
+				resolve: func(path string) (vdOperand, bool) {
+					msg, code := m.Message, "x"
+					parts := strings.Split(path, ".")
+					for i, part := range parts {
+						f := findVdField(msg, part)
+						if f == nil {
+							return vdOperand{}, false
+						}
+						if i == len(parts)-1 {
+							return vdFieldOperand(code, f), true
+						}
+						if f.Message == nil || f.Desc.IsList() || f.Desc.IsMap() {
+							return vdOperand{}, false
+						}
+						msg, code = f.Message, code+".Get"+f.GoName+"()"
+					}
+					return vdOperand{}, false
+				},
+				ident: ident,
+				regexp: func(pattern string) string {
+					v := fmt.Sprintf("_%s_vdRegexp%d", m.GoIdent.GoName, len(regexps))
+					regexps = append(regexps, fmt.Sprintf("%s = %s(%s)", v, ident("regexp", "MustCompile"), strconv.Quote(pattern)))
+					return v
+				},
+			}
+			var cond string
+			if cond, err = translateVd(rule.expr, env); err == nil {
+				msg := rule.msg
+				if msg == "" {
+					msg = fmt.Sprintf("invalid %s: %s", name, rule.expr)
+				}
+				g.P("if !", cond, " {")
+				g.P("return ", ident("errors", "New"), "(", strconv.Quote(msg), ")")
+				g.P("}")
+			}
+		}
+		if err != nil {
+			logs.Warnf("vd \"%s\" of field %s is not checked by Validate: %v", vd, name, err)
+		}
+	}
+	for _, field := range m.Fields {
+		if field.Message == nil || field.Desc.IsMap() || !needsValidate(field.Message, map[*protogen.Message]bool{}) {
+			continue
+		}
+		value := "x.Get" + field.GoName + "()"
+		if field.Desc.IsList() {
+			g.P("for _, v := range ", value, " {")
+			value = "v"
+		}
+		// messages of other files may have been generated without Validate
+		if field.Message.Desc.ParentFile() != m.Desc.ParentFile() {
+			g.P("if v, ok := interface{}(", value, ").(interface{ Validate() error }); ok {")
+			value = "v"
+		}
+		g.P("if err := ", value, ".Validate(); err != nil {")
+		g.P("return err")
+		g.P("}")
+		if field.Message.Desc.ParentFile() != m.Desc.ParentFile() {
+			g.P("}")
+		}
+		if field.Desc.IsList() {
+			g.P("}")
+		}
+	}
+	g.P("return nil")
+	g.P("}")
+	g.P()
+	if len(regexps) > 0 {
+		g.P("var (")
+		for _, r := range regexps {
+			g.P(r)
+		}
+		g.P(")")
+		g.P()
+	}
+}
@@ -0,0 +1,80 @@
+package protobuf
+
+import (
+	"testing"
+)
+
+func TestTranslateVd(t *testing.T) {
+	fields := map[string]vdOperand{
+		"Name":  {"x.GetName()", vdString},
+		"Start": {"float64(x.GetStart())", vdNumber},
+		"Ids":   {"x.GetIds()", vdList},
+		"Page":  {"x.GetPage()", vdMessage},
+	}
+	newEnv := func(self string) *vdEnv {
+		return &vdEnv{
+			self: fields[self],
+			resolve: func(path string) (vdOperand, bool) {
+				op, ok := fields[path]
+				return op, ok
+			},
+			ident: func(importPath, name string) string {
+				return importPath + "." + name
+			},
+			regexp: func(pattern string) string {
+				return "re(" + pattern + ")"
+			},
+		}
+	}
+
+	cases := []struct {
+		self string
+		vd   string
+		want string
+		msg  string
+	}{
+		{"Name", "$!=''", `(x.GetName() != "")`, ""},
+		{"Name", "len($)>0 && len($)<=64; msg:'bad name'", `((float64(len(x.GetName())) > 0) && (float64(len(x.GetName())) <= 64))`, "bad name"},
+		{"Name", "@:mblen($)<10", `(float64(unicode/utf8.RuneCountInString(x.GetName())) < 10)`, ""},
+		{"Name", `regexp('^\d+$')`, `re(^\d+$).MatchString(x.GetName())`, ""},
+		{"Name", "in($,'a','b')", `((x.GetName() == "a") || (x.GetName() == "b"))`, ""},
+		{"Start", "$>=1&&$<=(Start)*2", `((float64(x.GetStart()) >= 1) && (float64(x.GetStart()) <= (float64(x.GetStart()) * 2)))`, ""},
+		{"Start", "!($<0) || $%2==0", `(!(float64(x.GetStart()) < 0) || (math.Mod(float64(x.GetStart()), 2) == 0))`, ""},
+		{"Ids", "$!=nil && len($)>=1", `((x.GetIds() != nil) && (float64(len(x.GetIds())) >= 1))`, ""},
+		{"Page", "(Name)!='' || $==nil", `((x.GetName() != "") || (x.GetPage() == nil))`, ""},
+	}
+	for _, c := range cases {
+		rule, err := parseVdRule(c.vd)
+		if err != nil {
+			t.Fatalf("parse %s failed: %v", c.vd, err)
+		}
+		got, err := translateVd(rule.expr, newEnv(c.self))
+		if err != nil {
+			t.Fatalf("translate %s failed: %v", c.vd, err)
+		}
+		if got != c.want || rule.msg != c.msg {
+			t.Errorf("translate %s:\n got %s %q\nwant %s %q", c.vd, got, rule.msg, c.want, c.msg)
+		}
+	}
+
+	unsupported := []struct {
+		self string
+		vd   string
+	}{
+		{"Name", "$>0"},
+		{"Name", "len($)"},
+		{"Name", "email($)"},
+		{"Start", "$!=nil"},
+		{"Name", "(Unknown)!=''"},
+		{"Name", "$!=''; msg:sprintf('%v',$)"},
+	}
+	for _, c := range unsupported {
+		rule, err := parseVdRule(c.vd)
+		if err == nil {
+			_, err = translateVd(rule.expr, newEnv(c.self))
+		}
+		if err == nil {
+			t.Errorf("%s is expected to be unsupported", c.vd)
+		}
+	}
+}
@@ -18,18 +18,35 @@ var httpClientTpl = `// Licensed under the Apache License, Version 2.0 (the "Lic
 package {{.PackageName}}
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
+	stdErrors "errors"
 	"fmt"
 	"io"
+	"math"
+	"mime/multipart"
+	"net"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
-	
+	"sync"
+	"syscall"
+	"time"
+	{{if not .Stdlib}}
 	cli "github.com/telecom-cloud/client-go/pkg/client"
 	"github.com/telecom-cloud/client-go/pkg/common/config"
 	"github.com/telecom-cloud/client-go/pkg/common/errors"
@@ -37,16 +54,168 @@ import (
 	"github.com/telecom-cloud/client-go/pkg/openapi"
 	apiCfg "github.com/telecom-cloud/client-go/pkg/openapi/config"
 	apiErr "github.com/telecom-cloud/client-go/pkg/openapi/errors"
-	"github.com/telecom-cloud/client-go/pkg/openapi/signer"
 	"github.com/telecom-cloud/client-go/pkg/protocol"
 	"github.com/telecom-cloud/client-go/pkg/protocol/client"
+	{{- end}}
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// runtimeVersion is the runtime and its revision this file, signer.go and recorder.go were generated with,
+// cft regenerates them when the clients it generates need another one
+const runtimeVersion = "{{.RuntimeVersion}}"
+{{if .Stdlib}}
+// Doer sends the requests of the client, *http.Client is the default one
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function to Doer
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer of the client, the first registered one runs outermost
+type Middleware func(next Doer) Doer
+
+// ClientOption configures the default *http.Client
+type ClientOption func(c *http.Client)
+
+// WithTLSConfig sets the TLS configuration of the default transport
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *http.Client) {
+		transportOf(c).TLSClientConfig = cfg
+	}
+}
+
+// WithTimeout limits the time of every request, including reading the response body
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *http.Client) {
+		c.Timeout = timeout
+	}
+}
+
+// WithTransport replaces the transport of the default client
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *http.Client) {
+		c.Transport = transport
+	}
+}
+
+// transportOf returns the *http.Transport of c, replacing a transport of any other type
+func transportOf(c *http.Client) *http.Transport {
+	transport, ok := c.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		c.Transport = transport
+	}
+	return transport
+}
+
+// RequestOption adjusts a single request right before it is sent
+type RequestOption func(req *http.Request)
+
+// RawResponse is the response returned along with the result of every method
+type RawResponse = http.Response
+
+// OpenapiConfig carries the keys requests are signed with
+type OpenapiConfig struct {
+	AccessKey string
+	SecretKey string
+}
+
+// OpenapiResponse is the envelope of the openapi responses, the result is decoded into ReturnObj
+type OpenapiResponse struct {
+	StatusCode interface{} ` + "`json:\"statusCode\"`" + `
+	ErrorCode  string      ` + "`json:\"errorCode\"`" + `
+	Error      string      ` + "`json:\"error\"`" + `
+	Message    string      ` + "`json:\"message\"`" + `
+	ReturnObj  interface{} ` + "`json:\"returnObj\"`" + `
+}
+
+// ParseStatusCode returns the statusCode of the envelope, which is sent either as a number or as a string
+func (r *OpenapiResponse) ParseStatusCode() int {
+	switch code := r.StatusCode.(type) {
+	case float64:
+		return int(code)
+	case string:
+		n, _ := strconv.Atoi(code)
+		return n
+	default:
+		return 0
+	}
+}
+
+func bindResponse(body string, v interface{}) error {
+	if strings.TrimSpace(body) == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(body), v)
+}
+
+// Status describes a failed request
+type Status struct {
+	RequestId string
+	Code      int32
+	Reason    string
+	Message   string
+}
+
+// StatusError is returned for the responses which report a failure
+type StatusError struct {
+	ErrStatus Status
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request %s failed: code %d, reason %s, message %s",
+		e.ErrStatus.RequestId, e.ErrStatus.Code, e.ErrStatus.Reason, e.ErrStatus.Message)
+}
+
+// OptimizeQueryParams drops the query parameters which are not set
+func OptimizeQueryParams(params map[string]interface{}) {
+	for k, v := range params {
+		if v == nil {
+			delete(params, k)
+			continue
+		}
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map:
+			if rv.Len() == 0 {
+				delete(params, k)
+			}
+		case reflect.Ptr, reflect.Interface:
+			if rv.IsNil() {
+				delete(params, k)
+			}
+		}
+	}
+}
+{{else}}
 var OptimizeQueryParams = utils.OptimizeQueryParams
 
+// The client-go types are aliased by the names the stdlib runtime defines them with,
+// so the generated clients read the same on both runtimes
+type (
+	Doer            = client.Doer
+	Middleware      = cli.Middleware
+	ClientOption    = config.ClientOption
+	RequestOption   = config.RequestOption
+	RawResponse     = protocol.Response
+	OpenapiConfig   = apiCfg.OpenapiConfig
+	OpenapiResponse = openapi.Response
+	Status          = apiErr.Status
+	StatusError     = apiErr.StatusError
+)
+
+var bindResponse = openapi.BindResponse
+
 type use interface {
-	Use(mws ...cli.Middleware)
+	Use(mws ...Middleware)
 }
+{{end}}
 
 // ResponseResultDecider Definition of global data and types.
 type ResponseResultDecider func (*response) error
@@ -57,9 +226,55 @@ type (
 	afterResponseFunc   func(*HttpClient, *response) error
 )
 
+// CallInfo describes the IDL method a request is sent for
+type CallInfo struct {
+	Service string
+	Method  string
+	Route   string // route in the IDL, before the path params are filled in
+	Verb    string
+}
+
+// Tracer starts a span named Service.Method for every call of the client
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is the span of one call, it is given the verb, route, status code and request id of the call
+type Span interface {
+	SetAttributes(attrs map[string]string)
+	RecordError(err error)
+	End()
+}
+
+// Metrics records the latency of every call, and the reason of the failed ones, labelled by Service.Method
+type Metrics interface {
+	ObserveLatency(method string, latency time.Duration)
+	IncError(method, reason string)
+}
+
+// attributes set on the span of a call
+const (
+	AttrHttpMethod = "http.request.method"
+	AttrHttpRoute  = "http.route"
+	AttrStatusCode = "http.response.status_code"
+	AttrRequestId  = "http.request_id"
+)
+
+// RequestInterceptor runs before a request is signed and sent, headers added to header are signed too.
+// req is the typed request of the method, returning an error aborts the call.
+type RequestInterceptor func(ctx context.Context, info *CallInfo, req interface{}, header http.Header) error
+
+// ResponseInterceptor runs after the response is decoded, resp is the typed response which may be modified,
+// or nil when err is set. The returned error replaces err.
+type ResponseInterceptor func(ctx context.Context, info *CallInfo, req, resp interface{}, err error) error
+
+// HeaderRequestId is the header every request is sent with its request id in
+const HeaderRequestId = "X-Request-Id"
+
 var (
 	hdrContentTypeKey     = http.CanonicalHeaderKey("Content-Type")
 	hdrContentEncodingKey = http.CanonicalHeaderKey("Content-Encoding")
+	hdrCookieKey          = http.CanonicalHeaderKey("Cookie")
 
 	plainTextType   = "text/plain; charset=utf-8"
 	jsonContentType = "application/json; charset=utf-8"
@@ -68,6 +283,8 @@ var (
 	jsonCheck = regexp.MustCompile(` + "`(?i:(application|text)/(json|.*\\+json|json\\-.*)(;|$))`)\n" +
 	`xmlCheck  = regexp.MustCompile(` + "`(?i:(application|text)/(xml|.*\\+xml)(;|$))`)\n" +
 	`formUrlCheck  = regexp.MustCompile(` + "`(?i:(application|text)/(x-www-form-urlencoded|.*\\+x-www-form-urlencoded)(;|$))`)\n" +
+	`protobufCheck = regexp.MustCompile(` + "`(?i:application/(x-protobuf|protobuf|x-google-protobuf|vnd\\.google\\.protobuf)(;|$))`)\n" +
+	`textCheck     = regexp.MustCompile(` + "`(?i:text/plain(;|$))`)\n" +
 	`
 )
 
@@ -78,13 +295,33 @@ type Option struct {
 
 type Options struct {
 	hostUrl               string
-	doer                  client.Doer
+	overrideHostUrl       bool
+	endpoints             []string
+	endpointHealth        *endpointHealth
+	region                string
+	regionHosts           map[string]string
+	doer                  Doer
 	header                http.Header
 	requestBodyBind       bindRequestBodyFunc
 	responseResultDecider ResponseResultDecider
-    cfg                   *apiCfg.OpenapiConfig
-	middlewares           []cli.Middleware
-	clientOption          []config.ClientOption
+    cfg                   *OpenapiConfig
+	signer                Signer
+	middlewares           []Middleware
+	clientOption          []ClientOption
+	requestInterceptors   []RequestInterceptor
+	responseInterceptors  []ResponseInterceptor
+	tracer                Tracer
+	metrics               Metrics
+	rateLimiter           RateLimiter
+	methodRateLimiters    map[string]RateLimiter
+	inFlight              inFlightLimiter
+	breaker               *circuitBreaker
+	skipValidation        bool
+	requestIdFunc         func(ctx context.Context) string
+	debug                 *debugWriter
+	serviceOptions        map[string][]Option
+	tls                   TLSConfig
+	customTLS             bool
 }
 
 func GetOptions(ops ...Option) *Options {
@@ -96,28 +333,44 @@ func GetOptions(ops ...Option) *Options {
 }
 
 // WithClientOption is used to pass configuration for the crafter client
-func WithClientOption(opt ...config.ClientOption) Option {
+func WithClientOption(opt ...ClientOption) Option {
 	return Option{func(op *Options) {
 		op.clientOption = append(op.clientOption, opt...)
 	}}
 }
 
-// WithClientConfig is used to pass openapi configuration for the client
-func WithClientConfig(cfg *apiCfg.OpenapiConfig) Option {
+// WithClientConfig is used to pass openapi configuration for the client,
+// requests are signed with its keys, or with DefaultCredentials when it carries none
+func WithClientConfig(cfg *OpenapiConfig) Option {
 	return Option{func(op *Options) {
 		op.cfg = cfg
 	}}
 }
 
+// WithSigner signs every request with signer, it takes precedence over WithClientConfig.
+// Without either, requests are signed with DefaultCredentials when they are found
+func WithSigner(signer Signer) Option {
+	return Option{func(op *Options) {
+		op.signer = signer
+	}}
+}
+
+// WithCredentialProvider signs every request by OpenApiSigner with the credentials of provider
+func WithCredentialProvider(provider CredentialProvider) Option {
+	return Option{func(op *Options) {
+		op.signer = OpenApiSigner(provider)
+	}}
+}
+
 // WithClientMiddleware is used to register the middleware for the crafter client
-func WithClientMiddleware(mws ...cli.Middleware) Option {
+func WithClientMiddleware(mws ...Middleware) Option {
 	return Option{func(op *Options) {
 		op.middlewares = append(op.middlewares, mws...)
 	}}
 }
 
 // WithClient is used to register a custom crafter client
-func WithClient(client client.Doer) Option {
+func WithClient(client Doer) Option {
 	return Option{func(op *Options) {
 		op.doer = client
 	}}
@@ -137,82 +390,995 @@ func WithResponseResultDecider(decider ResponseResultDecider) Option {
 	}}
 }
 
+// WithHostUrl sets the host of every request, including the methods annotated with their own host,
+// it replaces the endpoints set before it
 func WithHostUrl(HostUrl string) Option {
 	return Option{func(op *Options) {
 		op.hostUrl = HostUrl
+		op.overrideHostUrl = true
+		op.endpoints = nil
+	}}
+}
+
+// WithEndpoints sends requests to the first reachable one of endpoints in place of the host of the client.
+// A request fails over to the next endpoint only when it could not connect to one (dial, DNS or refused
+// connection errors), any other error or response is returned as it is, as the client retries nothing.
+// An endpoint which could not be reached is tried after the others for 30s, and preferred again once
+// a request reaches it. What is learnt of the endpoints is shared by the clients created with the option.
+func WithEndpoints(endpoints ...string) Option {
+	health := newEndpointHealth()
+	return Option{func(op *Options) {
+		op.endpoints = endpoints
+		op.endpointHealth = health
+	}}
+}
+
+// WithRegion sends requests to the host of region in place of the host of the client,
+// the hosts of the regions are given by WithRegionHosts
+func WithRegion(region string) Option {
+	return Option{func(op *Options) {
+		op.region = region
+	}}
+}
+
+// WithRegionHosts adds the hosts of regions selected by WithRegion
+func WithRegionHosts(hosts map[string]string) Option {
+	return Option{func(op *Options) {
+		if op.regionHosts == nil {
+			op.regionHosts = make(map[string]string, len(hosts))
+		}
+		for region, host := range hosts {
+			op.regionHosts[region] = host
+		}
+	}}
+}
+
+// WithRequestInterceptor adds interceptors run in order before every request is sent
+func WithRequestInterceptor(interceptors ...RequestInterceptor) Option {
+	return Option{func(op *Options) {
+		op.requestInterceptors = append(op.requestInterceptors, interceptors...)
+	}}
+}
+
+// WithResponseInterceptor adds interceptors run in order after every response is decoded
+func WithResponseInterceptor(interceptors ...ResponseInterceptor) Option {
+	return Option{func(op *Options) {
+		op.responseInterceptors = append(op.responseInterceptors, interceptors...)
 	}}
 }
 
+// WithTracer traces every call of the client with tracer
+func WithTracer(tracer Tracer) Option {
+	return Option{func(op *Options) {
+		op.tracer = tracer
+	}}
+}
+
+// WithMetrics records the latency and the errors of every call of the client to metrics
+func WithMetrics(metrics Metrics) Option {
+	return Option{func(op *Options) {
+		op.metrics = metrics
+	}}
+}
+
+// WithSkipValidation sends requests without checking them by their Validate method
+func WithSkipValidation() Option {
+	return Option{func(op *Options) {
+		op.skipValidation = true
+	}}
+}
+
+// WithRequestIdFunc takes the X-Request-Id of the requests from their context, such as the id of the trace
+// they belong to, in place of a random one when the context carries no id set by WithRequestId
+func WithRequestIdFunc(f func(ctx context.Context) string) Option {
+	return Option{func(op *Options) {
+		op.requestIdFunc = f
+	}}
+}
+
+// TLSConfig configures how the default doer verifies servers and authenticates itself,
+// servers are verified against the system roots and the CA bundle unless InsecureSkipVerify is set
+type TLSConfig struct {
+	CAFile             string // PEM bundle trusted along with the system roots
+	CAPEM              []byte
+	CertFile           string // client certificate for mutual TLS
+	KeyFile            string
+	CertPEM            []byte
+	KeyPEM             []byte
+	MinVersion         uint16 // tls.VersionTLS12 when zero
+	ServerName         string // overrides the name servers are verified by
+	InsecureSkipVerify bool   // accepts any server certificate, for lab environments only
+}
+
+func (t TLSConfig) build() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         t.MinVersion,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if cfg.MinVersion == 0 {
+		cfg.MinVersion = tls.VersionTLS12
+	}
+	if t.CAFile != "" || len(t.CAPEM) != 0 {
+		bundle := append([]byte{}, t.CAPEM...)
+		if t.CAFile != "" {
+			data, err := os.ReadFile(t.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read CA file failed: %v", err)
+			}
+			bundle = append(append(bundle, '\n'), data...)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificate found in the CA bundle")
+		}
+		cfg.RootCAs = pool
+	}
+	switch {
+	case t.CertFile != "" || t.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate failed: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case len(t.CertPEM) != 0 || len(t.KeyPEM) != 0:
+		cert, err := tls.X509KeyPair(t.CertPEM, t.KeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parse client certificate failed: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func withTLS(f func(t *TLSConfig)) Option {
+	return Option{func(op *Options) {
+		f(&op.tls)
+		op.customTLS = true
+	}}
+}
+
+// WithTLS replaces the TLS configuration of the default doer
+func WithTLS(cfg TLSConfig) Option {
+	return withTLS(func(t *TLSConfig) {
+		*t = cfg
+	})
+}
+
+// WithCAFile trusts the PEM certificates in file along with the system roots
+func WithCAFile(file string) Option {
+	return withTLS(func(t *TLSConfig) {
+		t.CAFile = file
+	})
+}
+
+// WithCAPEM trusts the PEM certificates in pem along with the system roots
+func WithCAPEM(pem []byte) Option {
+	return withTLS(func(t *TLSConfig) {
+		t.CAPEM = pem
+	})
+}
+
+// WithClientCertificate authenticates the client by the PEM certificate and key files for mutual TLS
+func WithClientCertificate(certFile, keyFile string) Option {
+	return withTLS(func(t *TLSConfig) {
+		t.CertFile, t.KeyFile = certFile, keyFile
+	})
+}
+
+// WithClientCertificatePEM authenticates the client by the PEM certificate and key for mutual TLS
+func WithClientCertificatePEM(cert, key []byte) Option {
+	return withTLS(func(t *TLSConfig) {
+		t.CertPEM, t.KeyPEM = cert, key
+	})
+}
+
+// WithMinTLSVersion sets the lowest TLS version accepted, such as tls.VersionTLS13
+func WithMinTLSVersion(version uint16) Option {
+	return withTLS(func(t *TLSConfig) {
+		t.MinVersion = version
+	})
+}
+
+// WithServerName verifies servers by name instead of by the host of the request
+func WithServerName(name string) Option {
+	return withTLS(func(t *TLSConfig) {
+		t.ServerName = name
+	})
+}
+
+// WithInsecureSkipVerify accepts any server certificate, which exposes the requests
+// to man-in-the-middle attacks. It is meant for lab environments only.
+func WithInsecureSkipVerify() Option {
+	return withTLS(func(t *TLSConfig) {
+		t.InsecureSkipVerify = true
+	})
+}
+
+// WithServiceOption applies opts to the client of service in a ClientSet only, service is the name
+// the ClientSet method of the client is called by. The client keeps sharing the connections of the set
+// unless opts change its transport.
+func WithServiceOption(service string, opts ...Option) Option {
+	return Option{func(op *Options) {
+		if op.serviceOptions == nil {
+			op.serviceOptions = make(map[string][]Option)
+		}
+		op.serviceOptions[service] = append(op.serviceOptions[service], opts...)
+	}}
+}
+
+// newServiceHttpClient builds the HttpClient of a service given its own options in a ClientSet,
+// it reuses the doer of shared when the service options leave the transport alone
+func newServiceHttpClient(shared *HttpClient, hostUrl string, options, serviceOptions []Option) (*HttpClient, error) {
+	own := GetOptions(serviceOptions...)
+	opts := GetOptions(append(options[:len(options):len(options)], serviceOptions...)...)
+	if !opts.overrideHostUrl {
+		opts.hostUrl = hostUrl
+	}
+	if own.doer == nil && len(own.clientOption) == 0 && len(own.middlewares) == 0 && !own.customTLS {
+		// the middlewares are already wrapped around the shared doer
+		opts.doer, opts.middlewares = shared.doer, nil
+	}
+	return NewHttpClient(opts)
+}
+
+// WithDebug writes every request and its response to w, along with an equivalent curl command,
+// the credentials in the headers are redacted. Setting CRAFTER_CLIENT_DEBUG writes them to stderr.
+func WithDebug(w io.Writer) Option {
+	debug := &debugWriter{w: w}
+	return Option{func(op *Options) {
+		op.debug = debug
+	}}
+}
+
+// RateLimiter blocks until a request is allowed or ctx is done, *rate.Limiter of golang.org/x/time/rate fits it
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimiter limits the requests of the client with limiter
+func WithRateLimiter(limiter RateLimiter) Option {
+	return Option{func(op *Options) {
+		op.rateLimiter = limiter
+	}}
+}
+
+// WithRateLimit limits the requests to qps with bursts of burst requests.
+// The bucket is shared by all the clients created with the option, such as the clients of a ClientSet.
+func WithRateLimit(qps float64, burst int) Option {
+	limiter := NewTokenBucket(qps, burst)
+	return WithRateLimiter(limiter)
+}
+
+// WithMethodRateLimit limits the requests of method, named as Service.Method, in place of WithRateLimit
+func WithMethodRateLimit(method string, qps float64, burst int) Option {
+	limiter := NewTokenBucket(qps, burst)
+	return Option{func(op *Options) {
+		if op.methodRateLimiters == nil {
+			op.methodRateLimiters = map[string]RateLimiter{}
+		}
+		op.methodRateLimiters[method] = limiter
+	}}
+}
+
+// WithMaxInFlight limits the requests sent at the same time to n, which is no limit when n <= 0,
+// the limit is shared by all the clients created with the option
+func WithMaxInFlight(n int) Option {
+	var limiter inFlightLimiter
+	if n > 0 {
+		limiter = make(inFlightLimiter, n)
+	}
+	return Option{func(op *Options) {
+		op.inFlight = limiter
+	}}
+}
+
+// NewTokenBucket returns a RateLimiter refilled with qps tokens per second up to burst tokens
+func NewTokenBucket(qps float64, burst int) RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   qps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// Wait takes a token, waiting for the bucket to refill when it is empty
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	wait := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// give back the token reserved for the canceled request
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+type inFlightLimiter chan struct{}
+
+func (l inFlightLimiter) acquire(ctx context.Context) error {
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l inFlightLimiter) release() {
+	<-l
+}
+
+// CircuitState is the state of the circuit of a host
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails the requests fast until OpenTimeout has passed
+	CircuitOpen
+	// CircuitHalfOpen lets trial requests through, which close the circuit when they succeed
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return strconv.Itoa(int(s))
+}
+
+// ErrCircuitOpen is matched by the errors of the requests failed fast by an open circuit
+var ErrCircuitOpen = stdErrors.New("circuit breaker is open")
+
+// CircuitOpenError is returned without sending the request when the circuit of its host is open
+type CircuitOpenError struct {
+	Host string
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker of %s is open", e.Host)
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreakerConfig configures the circuit breaker of WithCircuitBreaker, responses of 5xx,
+// timeouts and connection errors count as failures
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the consecutive failures opening the circuit, 5 by default
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before trial requests are let through, 30s by default
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the trial requests which must all succeed to close the circuit, 1 by default
+	HalfOpenRequests int
+	// OnStateChange is called when the circuit of host changes state
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// WithCircuitBreaker fails the requests to a host fast while it keeps failing, the endpoints of
+// an open circuit are skipped by failover. The circuits are shared by all the clients created with the option.
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	breaker := newCircuitBreaker(config)
+	return Option{func(op *Options) {
+		op.breaker = breaker
+	}}
+}
+
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+	mu     sync.Mutex
+	hosts  map[string]*circuit
+}
+
+type circuit struct {
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	trials    int // trial requests let through in half-open
+	successes int
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+	return &circuitBreaker{config: config, hosts: make(map[string]*circuit)}
+}
+
+// allow takes a slot for a request to host, which is given back by done, trial tells the request
+// is one of the trials of the half-open circuit
+func (b *circuitBreaker) allow(host string) (trial bool, err error) {
+	b.mu.Lock()
+	c, ok := b.hosts[host]
+	if !ok {
+		c = &circuit{}
+		b.hosts[host] = c
+	}
+	var notify func()
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= b.config.OpenTimeout {
+		notify = b.transit(host, c, CircuitHalfOpen)
+	}
+	switch {
+	case c.state == CircuitOpen:
+		err = &CircuitOpenError{Host: host}
+	case c.state == CircuitHalfOpen && c.trials >= b.config.HalfOpenRequests:
+		err = &CircuitOpenError{Host: host}
+	case c.state == CircuitHalfOpen:
+		c.trials++
+		trial = true
+	}
+	b.mu.Unlock()
+	if notify != nil {
+		notify()
+	}
+	return trial, err
+}
+
+// done gives back the slot of a request to host, counting its outcome unless it is not counted.
+// The requests let through before the circuit changed state tell nothing of the current one
+func (b *circuitBreaker) done(host string, trial, failed, counted bool) {
+	b.mu.Lock()
+	c := b.hosts[host]
+	var notify func()
+	switch {
+	case c.state == CircuitClosed && !trial:
+		if !counted {
+			break
+		}
+		if !failed {
+			c.failures = 0
+		} else if c.failures++; c.failures >= b.config.FailureThreshold {
+			notify = b.transit(host, c, CircuitOpen)
+		}
+	case c.state == CircuitHalfOpen && trial:
+		switch {
+		case !counted:
+			c.trials--
+		case failed:
+			notify = b.transit(host, c, CircuitOpen)
+		default:
+			if c.successes++; c.successes >= b.config.HalfOpenRequests {
+				notify = b.transit(host, c, CircuitClosed)
+			}
+		}
+	}
+	b.mu.Unlock()
+	if notify != nil {
+		notify()
+	}
+}
+
+// transit moves c to state, the returned func calls OnStateChange once the lock is released
+func (b *circuitBreaker) transit(host string, c *circuit, state CircuitState) func() {
+	from := c.state
+	c.state, c.failures, c.trials, c.successes = state, 0, 0, 0
+	if state == CircuitOpen {
+		c.openedAt = time.Now()
+	}
+	if b.config.OnStateChange == nil {
+		return nil
+	}
+	return func() {
+		b.config.OnStateChange(host, from, state)
+	}
+}
+
+// circuitOutcome tells whether a request failed in the eyes of the circuit breaker,
+// requests given up by the caller are not counted
+func circuitOutcome(resp *response, err error) (failed, counted bool) {
+	var netErr net.Error
+	switch {
+	case isUnreachable(err), stdErrors.Is(err, context.DeadlineExceeded), stdErrors.As(err, &netErr) && netErr.Timeout():
+		return true, true
+	case stdErrors.Is(err, context.Canceled):
+		return false, false
+	case resp != nil && resp.RawResponse != nil && resp.StatusCode() != 0:
+		return resp.StatusCode() >= http.StatusInternalServerError, true
+	}
+	return err != nil, true
+}
+
+// checkCircuit fails req fast when the circuit of its host is open
+func checkCircuit(c *HttpClient, r *request) error {
+	if c.breaker == nil {
+		return nil
+	}
+	u, err := url.Parse(r.url)
+	if err != nil {
+		return err
+	}
+	if r.circuitTrial, err = c.breaker.allow(u.Host); err != nil {
+		return err
+	}
+	r.circuitHost = u.Host
+	return nil
+}
+
 // HttpClient underlying client
 type HttpClient struct {
 	hostUrl               string
-	doer                  client.Doer
+	overrideHostUrl       bool
+	endpoints             []string
+	endpointHealth        *endpointHealth
+	doer                  Doer
 	header                http.Header
-	cfg                   *apiCfg.OpenapiConfig
+	signer                Signer
 	bindRequestBody       bindRequestBodyFunc
 	responseResultDecider ResponseResultDecider
 
 	beforeRequest []beforeRequestFunc
 	afterResponse []afterResponseFunc
+
+	requestInterceptors  []RequestInterceptor
+	responseInterceptors []ResponseInterceptor
+	tracer               Tracer
+	metrics              Metrics
+	rateLimiter          RateLimiter
+	methodRateLimiters   map[string]RateLimiter
+	inFlight             inFlightLimiter
+	breaker              *circuitBreaker
+	skipValidation       bool
+	requestIdFunc        func(ctx context.Context) string
+	debug                *debugWriter
 }
 
-func (c *HttpClient) Use(mws ...cli.Middleware) error {
+func (c *HttpClient) Use(mws ...Middleware) error {
+	{{- if .Stdlib}}
+	for i := len(mws) - 1; i >= 0; i-- {
+		c.doer = mws[i](c.doer)
+	}
+	return nil
+	{{- else}}
 	u, ok := c.doer.(use)
 	if !ok {
 		return errors.NewPublic("doer does not support middleware, choose the right doer.")
 	}
-	u.Use(mws...)
-	return nil
+	u.Use(mws...)
+	return nil
+	{{- end}}
+}
+
+func NewHttpClient(opts *Options) (*HttpClient, error) {
+	if opts.requestBodyBind == nil {
+		opts.requestBodyBind = defaultRequestBodyBind
+	}
+	if opts.responseResultDecider == nil {
+		opts.responseResultDecider = defaultResponseResultDecider
+	}
+	if opts.signer == nil && opts.cfg != nil {
+		opts.signer = OpenApiSigner(ChainCredentials(
+			StaticCredentials(&Credentials{AccessKey: opts.cfg.AccessKey, SecretKey: opts.cfg.SecretKey}),
+			DefaultCredentials(),
+		))
+	}
+	if opts.signer == nil {
+		opts.signer = defaultSigner()
+	}
+	if opts.debug == nil && debugFromEnv() {
+		opts.debug = stderrDebug
+	}
+	endpoints := opts.endpoints
+	if opts.region != "" {
+		host, ok := opts.regionHosts[opts.region]
+		if !ok {
+			return nil, fmt.Errorf("unknown region %s", opts.region)
+		}
+		endpoints = []string{host}
+	}
+	health := opts.endpointHealth
+	if health == nil {
+		health = newEndpointHealth()
+	}
+	if opts.doer == nil {
+		tlsConfig, err := opts.tls.build()
+		if err != nil {
+			return nil, err
+		}
+		{{- if .Stdlib}}
+		httpClient := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+		WithTLSConfig(tlsConfig)(httpClient)
+		for _, option := range opts.clientOption {
+			option(httpClient)
+		}
+		opts.doer = httpClient
+		{{- else}}
+		// response bodies are streamed, so downloads are not held in memory
+		clientOption := append([]config.ClientOption{cli.WithResponseBodyStream(true), cli.WithTLSConfig(tlsConfig)}, opts.clientOption...)
+		cli, err := cli.NewClient(clientOption...)
+		if err != nil {
+			return nil, err
+		}
+		opts.doer = cli
+		{{- end}}
+	}
+
+	c := &HttpClient{
+		hostUrl:               opts.hostUrl,
+		overrideHostUrl:       opts.overrideHostUrl,
+		endpoints:             endpoints,
+		endpointHealth:        health,
+		doer:                  opts.doer,
+		header:                opts.header,
+		bindRequestBody:       opts.requestBodyBind,
+		responseResultDecider: opts.responseResultDecider,
+		beforeRequest: []beforeRequestFunc{
+			parseRequestURL,
+			interceptRequest,
+			parseRequestHeader,
+			// rejected requests are failed before their body is encoded or streamed
+			checkCircuit,
+			createHTTPRequest,
+		},
+		afterResponse: []afterResponseFunc{
+			parseResponseBody,
+		},
+		signer:               opts.signer,
+		requestInterceptors:  opts.requestInterceptors,
+		responseInterceptors: opts.responseInterceptors,
+		tracer:               opts.tracer,
+		metrics:              opts.metrics,
+		rateLimiter:          opts.rateLimiter,
+		methodRateLimiters:   opts.methodRateLimiters,
+		inFlight:             opts.inFlight,
+		breaker:              opts.breaker,
+		skipValidation:       opts.skipValidation,
+		requestIdFunc:        opts.requestIdFunc,
+		debug:                opts.debug,
+	}
+
+	if len(opts.middlewares) != 0 {
+		if err := c.Use(opts.middlewares...); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Execute sends req once the rate limit and the in-flight limit allow it,
+// traced and measured when a Tracer or Metrics is configured
+func (c *HttpClient) Execute(req *request) (*response, error) {
+	if req.ctx == nil {
+		req.ctx = context.Background()
+	}
+	c.setRequestIds(req)
+	method, route := req.method+" "+req.url, req.url
+	if req.callInfo != nil {
+		method = req.callInfo.Service + "." + req.callInfo.Method
+		route = req.callInfo.Route
+	}
+
+	limiter := c.rateLimiter
+	if l, ok := c.methodRateLimiters[method]; ok {
+		limiter = l
+	}
+	if limiter != nil {
+		if err := limiter.Wait(req.ctx); err != nil {
+			return nil, err
+		}
+	}
+	if c.inFlight != nil {
+		if err := c.inFlight.acquire(req.ctx); err != nil {
+			return nil, err
+		}
+		defer c.inFlight.release()
+	}
+
+	if c.tracer == nil && c.metrics == nil {
+		return c.execute(req)
+	}
+	var span Span
+	if c.tracer != nil {
+		req.ctx, span = c.tracer.Start(req.ctx, method)
+	}
+
+	start := time.Now()
+	resp, err := c.execute(req)
+
+	if c.metrics != nil {
+		c.metrics.ObserveLatency(method, time.Since(start))
+		if err != nil {
+			c.metrics.IncError(method, errorReason(err))
+		}
+	}
+	if span != nil {
+		attrs := map[string]string{
+			AttrHttpMethod: req.method,
+			AttrHttpRoute:  route,
+			AttrRequestId:  req.header.Get(HeaderRequestId),
+		}
+		if resp != nil && resp.RawResponse != nil {
+			attrs[AttrStatusCode] = strconv.Itoa(resp.StatusCode())
+			if requestId := resp.RawResponse.Header.Get(HeaderRequestId); requestId != "" {
+				attrs[AttrRequestId] = requestId
+			}
+		}
+		span.SetAttributes(attrs)
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}
+	return resp, err
+}
+
+// errorReason labels err in the error metrics, status errors by their reason or code
+func errorReason(err error) string {
+	var statusErr *StatusError
+	switch {
+	case stdErrors.As(err, &statusErr):
+		if statusErr.ErrStatus.Reason != "" {
+			return statusErr.ErrStatus.Reason
+		}
+		return strconv.Itoa(int(statusErr.ErrStatus.Code))
+	case stdErrors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case stdErrors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "error"
+	}
+}
+
+// endpointCooldown is how long an unreachable endpoint is tried after the others
+const endpointCooldown = 30 * time.Second
+
+// endpointHealth maps the endpoints which could not be reached to the time they are preferred again,
+// the clients of a set share it, so every one of them avoids an endpoint another found down
+type endpointHealth struct {
+	cooldown    time.Duration
+	mu          sync.Mutex
+	unreachable map[string]time.Time
+}
+
+func newEndpointHealth() *endpointHealth {
+	return &endpointHealth{cooldown: endpointCooldown, unreachable: make(map[string]time.Time)}
+}
+
+// failed tries endpoint after the others until the cooldown is over
+func (h *endpointHealth) failed(endpoint string) {
+	h.mu.Lock()
+	h.unreachable[endpoint] = time.Now().Add(h.cooldown)
+	h.mu.Unlock()
+}
+
+// reached prefers endpoint again
+func (h *endpointHealth) reached(endpoint string) {
+	h.mu.Lock()
+	delete(h.unreachable, endpoint)
+	h.mu.Unlock()
+}
+
+// order puts the endpoints found down last, keeping the order of the others
+func (h *endpointHealth) order(endpoints []string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	reachable := make([]string, 0, len(endpoints))
+	var unreachable []string
+	for _, endpoint := range endpoints {
+		until, ok := h.unreachable[endpoint]
+		if !ok {
+			reachable = append(reachable, endpoint)
+			continue
+		}
+		if now.Before(until) {
+			unreachable = append(unreachable, endpoint)
+			continue
+		}
+		// the cooldown is over, the endpoint is tried in its place again
+		delete(h.unreachable, endpoint)
+		reachable = append(reachable, endpoint)
+	}
+	return append(reachable, unreachable...)
+}
+
+// setRequestIds sets the request id and the idempotency key of req once, so that they stay the same
+// when req is sent to another endpoint
+func (c *HttpClient) setRequestIds(req *request) {
+	if req.header.Get(HeaderRequestId) == "" {
+		requestId, _ := req.ctx.Value(requestIdKey{}).(string)
+		if requestId == "" && c.requestIdFunc != nil {
+			requestId = c.requestIdFunc(req.ctx)
+		}
+		if requestId == "" {
+			requestId = newUUID()
+		}
+		req.header.Set(HeaderRequestId, requestId)
+	}
+	if req.idempotency != "" && req.header.Get(req.idempotency) == "" {
+		key, _ := req.ctx.Value(idempotencyKey{}).(string)
+		if key == "" {
+			key = newUUID()
+		}
+		req.header.Set(req.idempotency, key)
+	}
+}
+
+// responseRequestId is the request id the server answered with, or else the one the request was sent with
+func responseRequestId(res *response) string {
+	if requestId := res.Header().Get(HeaderRequestId); requestId != "" {
+		return requestId
+	}
+	return res.request.header.Get(HeaderRequestId)
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
 }
 
-func NewHttpClient(opts *Options) (*HttpClient, error) {
-	if opts.requestBodyBind == nil {
-		opts.requestBodyBind = defaultRequestBodyBind
+// execute sends req to the endpoints of the client in order, failing over to the next one
+// only when an endpoint cannot be reached, so a request is never processed twice
+func (c *HttpClient) execute(req *request) (*response, error) {
+	endpoints := c.endpointsOf(req)
+	if len(endpoints) == 0 {
+		return c.send(req)
 	}
-	if opts.responseResultDecider == nil {
-		opts.responseResultDecider = defaultResponseResultDecider
+	// uploaded readers cannot be sent twice
+	if len(req.uploadFiles) != 0 {
+		endpoints = endpoints[:1]
 	}
-	if opts.doer == nil {
-		cli, err := cli.NewClient(opts.clientOption...)
-		if err != nil {
-			return nil, err
+	route, header := req.url, req.header.Clone()
+	var (
+		resp *response
+		err  error
+	)
+	for _, endpoint := range endpoints {
+		req.url, req.header, req.endpoint = route, header.Clone(), endpoint
+		resp, err = c.send(req)
+		// endpoints of an open circuit are skipped without being sent to
+		if stdErrors.Is(err, ErrCircuitOpen) {
+			continue
+		}
+		if !isUnreachable(err) {
+			c.endpointHealth.reached(endpoint)
+			return resp, err
+		}
+		c.endpointHealth.failed(endpoint)
+		if req.ctx.Err() != nil {
+			break
 		}
-		opts.doer = cli
 	}
+	return resp, err
+}
 
-	c := &HttpClient{
-		hostUrl:               opts.hostUrl,
-		doer:                  opts.doer,
-		header:                opts.header,
-		bindRequestBody:       opts.requestBodyBind,
-		responseResultDecider: opts.responseResultDecider,
-		beforeRequest: []beforeRequestFunc{
-			parseRequestURL,
-			parseRequestHeader,
-			createHTTPRequest,
-		},
-		afterResponse: []afterResponseFunc{
-			parseResponseBody,
-		},
-		cfg: opts.cfg,
+// endpointsOf orders the endpoints req may be sent to, the reachable ones first,
+// requests to a host of their own are sent there alone
+func (c *HttpClient) endpointsOf(req *request) []string {
+	if len(c.endpoints) == 0 || (req.hostUrl != "" && !c.overrideHostUrl) {
+		return nil
+	}
+	return c.endpointHealth.order(c.endpoints)
+}
+
+// isUnreachable reports whether err tells the request never reached the server
+func isUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var opErr *net.OpError
+	if stdErrors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
 	}
+	var dnsErr *net.DNSError
+	return stdErrors.As(err, &dnsErr) || stdErrors.Is(err, syscall.ECONNREFUSED)
+}
 
-	if len(opts.middlewares) != 0 {
-		if err := c.Use(opts.middlewares...); err != nil {
-			return nil, err
-		}
+// send sends req once, dumping the exchange when debugging is on
+// and counting its outcome for the circuit of its host
+func (c *HttpClient) send(req *request) (*response, error) {
+	start := time.Now()
+	resp, err := c.do(req)
+	if req.circuitHost != "" {
+		failed, counted := circuitOutcome(resp, err)
+		c.breaker.done(req.circuitHost, req.circuitTrial, failed, counted)
+		req.circuitHost = ""
 	}
-	return c, nil
+	if c.debug != nil {
+		c.debug.dump(req, resp, err, time.Since(start))
+	}
+	return resp, err
 }
 
-func (c *HttpClient) Execute(req *request) (*response, error) {
+func (c *HttpClient) do(req *request) (*response, error) {
 	var err error
 	for _, f := range c.beforeRequest {
 		if err = f(c, req); err != nil {
+			closeRequestBody(req)
+			if req.circuitHost != "" {
+				// the request was never sent, so it tells nothing of its host
+				c.breaker.done(req.circuitHost, req.circuitTrial, false, false)
+				req.circuitHost = ""
+			}
 			return nil, err
 		}
 	}
 
+{{- if .Stdlib}}
+	resp, err := c.doer.Do(req.rawRequest)
+
+	response := &response{
+		request:     req,
+		RawResponse: resp,
+	}
+
+	if err != nil {
+		closeRequestBody(req)
+		return response, err
+	}
+
+	if req.output != nil && resp.StatusCode < http.StatusBadRequest {
+		response.size, err = streamResponseBody(resp, req.output, req.progress)
+		return response, err
+	}
+
+	defer resp.Body.Close()
+	var reader io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get(hdrContentEncodingKey), "gzip") && !resp.Uncompressed && resp.ContentLength != 0 {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+{{- else}}
 	if hostHeader := req.header.Get("Host"); hostHeader != "" {
 		req.rawRequest.Header.SetHost(hostHeader)
 	}
@@ -227,6 +1393,12 @@ func (c *HttpClient) Execute(req *request) (*response, error) {
 	}
 
 	if err != nil {
+		closeRequestBody(req)
+		return response, err
+	}
+
+	if req.output != nil && resp.StatusCode() < http.StatusBadRequest {
+		response.size, err = streamResponseBody(&resp, req.output, req.progress)
 		return response, err
 	}
 
@@ -241,6 +1413,7 @@ func (c *HttpClient) Execute(req *request) (*response, error) {
 			return nil, err
 		}
 	}
+{{- end}}
 
 	response.bodyByte = body
 
@@ -256,6 +1429,431 @@ func (c *HttpClient) Execute(req *request) (*response, error) {
 	return response, err
 }
 
+// closeRequestBody closes the body of a request which failed, doers are not bound to close it,
+// so the goroutine streaming a multipart body would be left blocked
+func closeRequestBody(req *request) {
+	if req.rawRequest == nil {
+		return
+	}
+	{{- if .Stdlib}}
+	if req.rawRequest.Body != nil {
+		req.rawRequest.Body.Close()
+	}
+	{{- else}}
+	if closer, ok := req.rawRequest.BodyStream().(io.Closer); ok {
+		closer.Close()
+	}
+	{{- end}}
+}
+
+// streamResponseBody copies the response body into w, decompressing gzip bodies
+{{- if .Stdlib}}
+func streamResponseBody(resp *http.Response, w io.Writer, progress ProgressFunc) (int64, error) {
+	defer resp.Body.Close()
+	var body io.Reader = resp.Body
+	total := resp.ContentLength
+	if strings.EqualFold(resp.Header.Get(hdrContentEncodingKey), "gzip") && !resp.Uncompressed && total != 0 {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		body = gz
+		total = -1
+	}
+{{- else}}
+func streamResponseBody(resp *protocol.Response, w io.Writer, progress ProgressFunc) (int64, error) {
+	var body io.Reader
+	if resp.IsBodyStream() {
+		body = resp.BodyStream()
+		defer resp.CloseBodyStream()
+	} else {
+		body = bytes.NewReader(resp.Body())
+	}
+	total := int64(resp.Header.ContentLength())
+	if strings.EqualFold(resp.Header.Get(hdrContentEncodingKey), "gzip") && total != 0 {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		body = gz
+		total = -1
+	}
+{{- end}}
+	if total < 0 {
+		total = -1
+	}
+	return io.Copy(w, &progressReader{r: body, total: total, progress: progress})
+}
+
+// EnvClientDebug turns on the request dumps of every client when set to anything but "false" or "0"
+const EnvClientDebug = "CRAFTER_CLIENT_DEBUG"
+
+// debugBodyLimit is the longest body written to a dump
+const debugBodyLimit = 64 << 10
+
+var stderrDebug = &debugWriter{w: os.Stderr}
+
+func debugFromEnv() bool {
+	v := strings.ToLower(os.Getenv(EnvClientDebug))
+	return v != "" && v != "false" && v != "0"
+}
+
+// debugWriter writes each dump at once, so the dumps of concurrent requests do not interleave
+type debugWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (d *debugWriter) dump(req *request, resp *response, err error, elapsed time.Duration) {
+	header := redactHeader(req.header)
+	body := debugRequestBody(req)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "> %s %s\n", req.method, req.url)
+	writeDebugHeader(&buf, "> ", header)
+	buf.WriteString(">\n")
+	writeDebugBody(&buf, body)
+
+	if resp != nil && resp.RawResponse != nil {
+		fmt.Fprintf(&buf, "< %d %s (%s)\n", resp.StatusCode(), http.StatusText(resp.StatusCode()), elapsed)
+		writeDebugHeader(&buf, "< ", redactHeader(resp.Header()))
+		buf.WriteString("<\n")
+		if req.output != nil && resp.bodyByte == nil {
+			fmt.Fprintf(&buf, "(%d bytes streamed to the output)\n", resp.size)
+		} else {
+			writeDebugBody(&buf, resp.bodyByte)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(&buf, "! %v (%s)\n", err, elapsed)
+	}
+	buf.WriteString(renderCurl(req, header, body))
+	buf.WriteString("\n\n")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.w.Write(buf.Bytes())
+}
+
+func isMultipart(req *request) bool {
+	return len(req.uploadFiles) != 0 || len(req.formParam) != 0 || len(req.fileParam) != 0
+}
+
+// multipartFields lists the fields of a multipart request as the -F arguments of curl
+func multipartFields(req *request) []string {
+	var fields []string
+	for _, name := range sortedKeys(req.formParam) {
+		fields = append(fields, name+"="+req.formParam[name])
+	}
+	for _, name := range sortedKeys(req.fileParam) {
+		if _, ok := req.uploadFiles[name]; !ok && req.fileParam[name] != "" {
+			fields = append(fields, name+"=@"+req.fileParam[name])
+		}
+	}
+	names := make([]string, 0, len(req.uploadFiles))
+	for name, file := range req.uploadFiles {
+		if file != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fields = append(fields, name+"=@"+req.uploadFiles[name].FileName)
+	}
+	return fields
+}
+
+// debugRequestBody renders the body of req, multipart forms are listed by their fields
+func debugRequestBody(req *request) []byte {
+	if !isPayloadSupported(req.method) {
+		return nil
+	}
+	if isMultipart(req) {
+		return []byte(strings.Join(multipartFields(req), "\n"))
+	}
+	if req.rawBody != nil {
+		return req.rawBody
+	}
+	body, _ := encodeRequestBody(req.header.Get(hdrContentTypeKey), req.bodyParam)
+	return body
+}
+
+// isSensitiveHeader reports whether the value of the header carries credentials
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "authorization") || strings.Contains(name, "signature") ||
+		strings.Contains(name, "token") || name == "cookie" || name == "set-cookie"
+}
+
+func redactHeader(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for k, v := range header {
+		if isSensitiveHeader(k) {
+			v = []string{"[REDACTED]"}
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+func writeDebugHeader(buf *bytes.Buffer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for k := range header {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		for _, v := range header[k] {
+			fmt.Fprintf(buf, "%s%s: %s\n", prefix, k, v)
+		}
+	}
+}
+
+func writeDebugBody(buf *bytes.Buffer, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	if len(body) > debugBodyLimit {
+		fmt.Fprintf(buf, "%s\n(%d more bytes)\n", body[:debugBodyLimit], len(body)-debugBodyLimit)
+		return
+	}
+	buf.Write(body)
+	buf.WriteString("\n")
+}
+
+// renderCurl renders a curl command sending the same request
+func renderCurl(req *request, header http.Header, body []byte) string {
+	multipart := isPayloadSupported(req.method) && isMultipart(req)
+	var buf strings.Builder
+	buf.WriteString("curl -X " + req.method + " " + shellQuote(req.url))
+	names := make([]string, 0, len(header))
+	for k := range header {
+		// curl sets the content type of a form along with its boundary
+		if multipart && k == hdrContentTypeKey {
+			continue
+		}
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		for _, v := range header[k] {
+			buf.WriteString(" -H " + shellQuote(k+": "+v))
+		}
+	}
+	if multipart {
+		for _, field := range multipartFields(req) {
+			buf.WriteString(" -F " + shellQuote(field))
+		}
+	} else if len(body) != 0 {
+		if len(body) > debugBodyLimit {
+			body = body[:debugBodyLimit]
+		}
+		buf.WriteString(" --data-binary " + shellQuote(string(body)))
+	}
+	return buf.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", ` + "`'\\''`" + `) + "'"
+}
+
+// ProgressFunc reports the bytes transferred so far, total is -1 when the size is unknown
+type ProgressFunc func(transferred, total int64)
+
+type progressReader struct {
+	r           io.Reader
+	transferred int64
+	total       int64
+	progress    ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.transferred += int64(n)
+		if p.progress != nil {
+			p.progress(p.transferred, p.total)
+		}
+	}
+	return n, err
+}
+
+// UploadFile is the content of a file field streamed from Reader instead of read from a local path
+type UploadFile struct {
+	FileName    string
+	ContentType string // application/octet-stream when empty
+	Reader      io.Reader
+	Size        int64 // total reported to Progress, unknown when not positive
+	Progress    ProgressFunc
+}
+
+// validate checks req by the Validate method generated from its api.vd annotations
+func (c *HttpClient) validate(req interface{}) error {
+	if v, ok := req.(interface{ Validate() error }); ok && !c.skipValidation {
+		return v.Validate()
+	}
+	return nil
+}
+
+// WaitOptions tells the XxxAndWait methods how to poll a long-running operation, the zero value polls
+// every 2s backing off by 1.5 up to 30s for as long as ctx allows. A poll answered with a 5xx or 429 code,
+// timing out or not reaching the server is retried on the same backoff, any other failure ends the wait.
+type WaitOptions struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Backoff     float64
+	// Timeout bounds the whole wait in addition to ctx
+	Timeout time.Duration
+	// PollRetries is the consecutive failed polls retried before the error is returned, 3 by default
+	PollRetries int
+	// Progress is called with the status of the operation after every poll
+	Progress func(status string, polls int)
+}
+
+// OperationError is returned by the XxxAndWait methods when the operation ends in a failure status
+type OperationError struct {
+	JobId  string
+	Status string
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %s failed with status %s", e.JobId, e.Status)
+}
+
+// waitOperation polls the operation of jobId until its status is one of success or failure
+func waitOperation(ctx context.Context, opts *WaitOptions, jobId string, poll func(ctx context.Context) (string, error), success, failure []string) error {
+	wait := WaitOptions{}
+	if opts != nil {
+		wait = *opts
+	}
+	if wait.Interval <= 0 {
+		wait.Interval = 2 * time.Second
+	}
+	if wait.MaxInterval <= 0 {
+		wait.MaxInterval = 30 * time.Second
+	}
+	if wait.Backoff < 1 {
+		wait.Backoff = 1.5
+	}
+	if wait.PollRetries <= 0 {
+		wait.PollRetries = 3
+	}
+	if wait.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wait.Timeout)
+		defer cancel()
+	}
+
+	interval, failed := wait.Interval, 0
+	for polls := 1; ; polls++ {
+		status, err := poll(ctx)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return fmt.Errorf("wait for operation %s: %w", jobId, ctx.Err())
+		case err != nil && (failed == wait.PollRetries || !retryablePoll(err)):
+			return err
+		case err != nil:
+			failed++
+		default:
+			failed = 0
+			if wait.Progress != nil {
+				wait.Progress(status, polls)
+			}
+			for _, s := range success {
+				if status == s {
+					return nil
+				}
+			}
+			for _, s := range failure {
+				if status == s {
+					return &OperationError{JobId: jobId, Status: status}
+				}
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("wait for operation %s: %w", jobId, ctx.Err())
+		case <-timer.C:
+		}
+		if interval = time.Duration(float64(interval) * wait.Backoff); interval > wait.MaxInterval {
+			interval = wait.MaxInterval
+		}
+	}
+}
+
+// retryablePoll reports whether a failed poll may succeed when it is sent again
+func retryablePoll(err error) bool {
+	var statusErr *StatusError
+	if stdErrors.As(err, &statusErr) {
+		code := statusErr.ErrStatus.Code
+		return code == http.StatusTooManyRequests || (code >= http.StatusInternalServerError && code < 600)
+	}
+	var netErr net.Error
+	return isUnreachable(err) || (stdErrors.As(err, &netErr) && netErr.Timeout())
+}
+
+type requestIdKey struct{}
+
+// WithRequestId sets the X-Request-Id of the requests made with ctx, so that they are correlated with
+// the incoming request, which is also the request id signed by OpenApiSigner
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey sets the idempotency key of the requests made with ctx to the methods annotated with
+// api.idempotency, a key is generated for every call otherwise
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+{{- if .Stdlib}}
+
+type httpMethodKey struct{}
+{{- else}}
+
+// httpMethodTag is the request tag WithHttpMethod sets
+const httpMethodTag = "crafter-http-method"
+{{- end}}
+
+// WithHttpMethod sends the methods bound with api.any with the verb method, they are sent as POST otherwise.
+// The other methods ignore it
+func WithHttpMethod(method string) RequestOption {
+	method = strings.ToUpper(method)
+	{{- if .Stdlib}}
+	return func(req *http.Request) {
+		if verb, ok := req.Context().Value(httpMethodKey{}).(*string); ok {
+			*verb = method
+		}
+	}
+	{{- else}}
+	return config.WithTag(httpMethodTag, method)
+	{{- end}}
+}
+
+// anyHttpMethod returns the verb WithHttpMethod sets among opts, POST by default
+func anyHttpMethod(opts []RequestOption) string {
+	{{- if .Stdlib}}
+	verb := http.MethodPost
+	probe := (&http.Request{Header: http.Header{}, URL: &url.URL{}}).WithContext(context.WithValue(context.Background(), httpMethodKey{}, &verb))
+	for _, opt := range opts {
+		opt(probe)
+	}
+	return verb
+	{{- else}}
+	if verb := config.NewRequestOptions(opts).Tag(httpMethodTag); verb != "" {
+		return verb
+	}
+	return http.MethodPost
+	{{- end}}
+}
+
 // R get request
 func (c *HttpClient) R() *request {
 	if c.header == nil {
@@ -263,10 +1861,12 @@ func (c *HttpClient) R() *request {
 	}
 	return &request{
 		queryParam:     url.Values{},
-		header:         c.header,
+		queryStyle:     map[string]string{},
+		header:         http.Header{},
 		pathParam:      map[string]string{},
 		formParam:      map[string]string{},
 		fileParam:      map[string]string{},
+		cookieParam:    map[string]string{},
 		client:         c,
 		queryEnumAsInt: {{.QueryEnumAsInt}},
 	}
@@ -274,7 +1874,7 @@ func (c *HttpClient) R() *request {
 
 type response struct {
 	request     *request
-	RawResponse *protocol.Response
+	RawResponse *RawResponse
 
 	bodyByte []byte
 	size     int64
@@ -286,7 +1886,11 @@ func (r *response) StatusCode() int {
 		return 0
 	}
 
+	{{- if .Stdlib}}
+	return r.RawResponse.StatusCode
+	{{- else}}
 	return r.RawResponse.StatusCode()
+	{{- end}}
 }
 
 // Body method returns HTTP response as []byte array for the executed request.
@@ -302,29 +1906,51 @@ func (r *response) Header() http.Header {
 	if r.RawResponse == nil {
 		return http.Header{}
 	}
+	{{- if .Stdlib}}
+	return r.RawResponse.Header.Clone()
+	{{- else}}
 	h := http.Header{}
 	r.RawResponse.Header.VisitAll(func(key, value []byte) {
 		h.Add(string(key), string(value))
 	})
 
 	return h
+	{{- end}}
 }
 
 type request struct {
 	client         *HttpClient
+	hostUrl        string
 	url            string
 	method         string
 	queryEnumAsInt bool
 	queryParam     url.Values
+	queryStyle     map[string]string
 	header         http.Header
 	pathParam      map[string]string
 	formParam      map[string]string
 	fileParam      map[string]string
+	cookieParam    map[string]string
+	uploadFiles    map[string]*UploadFile
 	bodyParam      interface{}
+	rawBody        []byte
+	endpoint       string
+	{{- if .Stdlib}}
+	rawRequest     *http.Request
+	{{- else}}
 	rawRequest     *protocol.Request
+	{{- end}}
 	ctx            context.Context
-	requestOptions []config.RequestOption
+	requestOptions []RequestOption
+	callInfo       *CallInfo
+	callReq        interface{}
+	output         io.Writer
+	progress       ProgressFunc
 	result         interface{}
+	envelope       *Envelope
+	idempotency    string // header of the idempotency key
+	circuitHost    string // host whose circuit let the request through
+	circuitTrial   bool   // the request was let through as a trial of the half-open circuit
 	Error          interface{}
 }
 
@@ -337,44 +1963,188 @@ func (r *request) Context() context.Context {
 	return r.ctx
 }
 
+// SetCallInfo sets the method and the typed request seen by the interceptors
+func (r *request) SetCallInfo(info *CallInfo, req interface{}) *request {
+	r.callInfo = info
+	r.callReq = req
+	return r
+}
+
+// SetHostUrl sends the request to hostUrl instead of the host of the client, unless WithHostUrl is given
+func (r *request) SetHostUrl(hostUrl string) *request {
+	r.hostUrl = hostUrl
+	return r
+}
+
 func (r *request) SetHeader(header, value string) *request {
 	r.header.Set(header, value)
 	return r
 }
 
-func (r *request) AddHeader(header, value string) *request {
-	r.header.Add(header, value)
-	return r
+func (r *request) AddHeader(header, value string) *request {
+	r.header.Add(header, value)
+	return r
+}
+
+func (r *request) AddHeaders(params map[string]string) *request {
+	for k, v := range params {
+		r.AddHeader(k, v)
+	}
+	return r
+}
+
+// SetQueryParam encodes value with the style set for param by SetQueryStyles
+func (r *request) SetQueryParam(param string, value interface{}) *request {
+	r.queryParam.Del(param)
+	r.encodeQuery(param, reflect.ValueOf(value), r.queryStyle[param])
+	return r
+}
+
+// SetQueryStyles sets the api.query_style of the query params, it must precede SetQueryParams
+func (r *request) SetQueryStyles(styles map[string]string) *request {
+	for p, s := range styles {
+		r.queryStyle[p] = s
+	}
+	return r
+}
+
+const (
+	queryStyleFormNoExplode = "form_no_explode"
+	queryStyleDeepObject    = "deep_object"
+	queryStyleJSON          = "json"
+)
+
+// encodeQuery adds v to the query under key, repeated values are exploded unless the style is
+// form_no_explode, and nested fields are named key.field, or key[field] with the deep_object style
+func (r *request) encodeQuery(key string, v reflect.Value, style string) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if style == queryStyleJSON {
+		if b, err := json.Marshal(v.Interface()); err == nil {
+			r.queryParam.Add(key, string(b))
+		}
+		return
+	}
+	if s, ok := r.queryScalar(v); ok {
+		r.queryParam.Add(key, s)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		var joined []string
+		for i := 0; i < v.Len(); i++ {
+			elem := reflect.Indirect(v.Index(i))
+			s, ok := r.queryScalar(elem)
+			switch {
+			case ok && style == queryStyleFormNoExplode:
+				joined = append(joined, s)
+			case ok:
+				r.queryParam.Add(key, s)
+			default:
+				r.encodeQuery(nestedQueryKey(key, strconv.Itoa(i), style), elem, style)
+			}
+		}
+		if len(joined) != 0 {
+			r.queryParam.Add(key, strings.Join(joined, ","))
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			r.encodeQuery(nestedQueryKey(key, fmt.Sprint(k.Interface()), style), v.MapIndex(k), style)
+		}
+	case reflect.Struct:
+		r.encodeQueryFields(key, v, style)
+	}
+}
+
+func (r *request) encodeQueryFields(key string, v reflect.Value, style string) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		// the fields of a oneof stay at the level of the message holding it
+		if _, ok := field.Tag.Lookup("protobuf_oneof"); ok {
+			if inner := v.Field(i); !inner.IsNil() {
+				r.encodeQueryFields(key, inner.Elem().Elem(), style)
+			}
+			continue
+		}
+		name := queryFieldName(field)
+		if name == "" {
+			continue
+		}
+		r.encodeQuery(nestedQueryKey(key, name, style), v.Field(i), style)
+	}
+}
+
+func nestedQueryKey(key, name, style string) string {
+	if style == queryStyleDeepObject {
+		return key + "[" + name + "]"
+	}
+	return key + "." + name
 }
 
-func (r *request) AddHeaders(params map[string]string) *request {
-	for k, v := range params {
-		r.AddHeader(k, v)
+// queryFieldName names a nested field after its query, json or protobuf name
+func queryFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"query", "json"} {
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name == "-" {
+			return ""
+		} else if name != "" {
+			return name
+		}
 	}
-	return r
+	for _, part := range strings.Split(field.Tag.Get("protobuf"), ",") {
+		if strings.HasPrefix(part, "name=") {
+			return strings.TrimPrefix(part, "name=")
+		}
+	}
+	return field.Name
 }
 
-func (r *request) SetQueryParam(param string, value interface{}) *request {
-	v := reflect.ValueOf(value)
-	switch v.Kind() {
-	case reflect.Slice, reflect.Array:
-		for index := 0; index < v.Len(); index++ {
-			if r.queryEnumAsInt && (v.Index(index).Kind() == reflect.Int32 || v.Index(index).Kind() == reflect.Int64) {
-				r.queryParam.Add(param, fmt.Sprintf("%d", v.Index(index).Interface()))
-			} else {
-				r.queryParam.Add(param, fmt.Sprint(v.Index(index).Interface()))
+// queryScalar formats the values sent as a single query value, enums are sent by name unless queryEnumAsInt
+func (r *request) queryScalar(v reflect.Value) (string, bool) {
+	if !v.IsValid() {
+		return "", false
+	}
+	if v.CanInterface() {
+		if e, ok := v.Interface().(protoreflect.Enum); ok {
+			if !r.queryEnumAsInt {
+				if ev := e.Descriptor().Values().ByNumber(e.Number()); ev != nil {
+					return string(ev.Name()), true
+				}
 			}
+			return strconv.FormatInt(int64(e.Number()), 10), true
 		}
-	case reflect.Int32, reflect.Int64:
-		if r.queryEnumAsInt {
-			r.queryParam.Add(param, fmt.Sprintf("%d", v.Interface()))
-		} else {
-			r.queryParam.Add(param, fmt.Sprint(v))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), true
 		}
-	default:
-		r.queryParam.Set(param, fmt.Sprint(v))
 	}
-	return r
+	return "", false
 }
 
 func (r *request) SetResult(res interface{}) *request {
@@ -382,11 +2152,29 @@ func (r *request) SetResult(res interface{}) *request {
 	return r
 }
 
+// SetIdempotencyHeader sends the idempotency key of the request in header
+func (r *request) SetIdempotencyHeader(header string) *request {
+	r.idempotency = header
+	return r
+}
+
+// SetEnvelope sets the envelope the response body is decoded by in place of the openapi response
+func (r *request) SetEnvelope(envelope *Envelope) *request {
+	r.envelope = envelope
+	return r
+}
+
 func (r *request) SetError(err interface{}) *request {
 	r.Error = err
 	return r
 }
 
+// SetContentType sets the content type of the request body, which also selects its codec
+func (r *request) SetContentType(contentType string) *request {
+	r.header.Set(hdrContentTypeKey, contentType)
+	return r
+}
+
 func (r *request) SetHeaders(headers map[string]string) *request {
 	for h, v := range headers {
 		r.SetHeader(h, v)
@@ -424,12 +2212,50 @@ func (r *request) SetFormFileParams(params map[string]string) *request {
 	return r
 }
 
+// SetUploadFiles streams files as multipart parts, replacing the file paths of the same fields
+func (r *request) SetUploadFiles(files map[string]*UploadFile) *request {
+	for p, f := range files {
+		if f == nil {
+			continue
+		}
+		if r.uploadFiles == nil {
+			r.uploadFiles = map[string]*UploadFile{}
+		}
+		r.uploadFiles[p] = f
+		delete(r.fileParam, p)
+	}
+	return r
+}
+
+// SetOutput streams the body of a successful response into w instead of decoding it
+func (r *request) SetOutput(w io.Writer, progress ProgressFunc) *request {
+	r.output = w
+	r.progress = progress
+	return r
+}
+
+func (r *request) SetCookies(params map[string]string) *request {
+	for p, v := range params {
+		r.cookieParam[p] = v
+	}
+	return r
+}
+
 func (r *request) SetBodyParam(body interface{}) *request {
 	r.bodyParam = body
 	return r
 }
 
-func (r *request) SetRequestOption(option ...config.RequestOption) *request {
+// SetRawBody sends body as is, with the Content-Type set on the request or detected from body
+func (r *request) SetRawBody(body []byte) *request {
+	if body == nil {
+		body = []byte{}
+	}
+	r.rawBody = body
+	return r
+}
+
+func (r *request) SetRequestOption(option ...RequestOption) *request {
 	r.requestOptions = append(r.requestOptions, option...)
 	return r
 }
@@ -440,6 +2266,26 @@ func (r *request) Execute(method, url string) (*response, error) {
 	return r.client.Execute(r)
 }
 
+func interceptRequest(c *HttpClient, r *request) error {
+	if r.callInfo != nil {
+		r.callInfo.Verb = r.method
+	}
+	for _, f := range c.requestInterceptors {
+		if err := f(r.ctx, r.callInfo, r.callReq, r.header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// interceptResponse runs the response interceptors of the client on the outcome of r
+func (r *request) interceptResponse(resp interface{}, err error) error {
+	for _, f := range r.client.responseInterceptors {
+		err = f(r.ctx, r.callInfo, r.callReq, resp, err)
+	}
+	return err
+}
+
 func parseRequestURL(c *HttpClient, r *request) error {
 	if len(r.pathParam) > 0 {
 		for p, v := range r.pathParam {
@@ -461,7 +2307,14 @@ func parseRequestURL(c *HttpClient, r *request) error {
 			r.url = "/" + r.url
 		}
 
-		reqURL, err = url.Parse(c.hostUrl + r.url)
+		hostUrl := c.hostUrl
+		if r.endpoint != "" {
+			hostUrl = r.endpoint
+		}
+		if r.hostUrl != "" && !c.overrideHostUrl {
+			hostUrl = r.hostUrl
+		}
+		reqURL, err = url.Parse(hostUrl + r.url)
 		if err != nil {
 			return err
 		}
@@ -512,42 +2365,66 @@ func parseRequestHeader(c *HttpClient, r *request) error {
 		hdr[k] = append(hdr[k], r.header[k]...)
 	}
 
-	if len(r.formParam) != 0 || len(r.fileParam) != 0 {
+	if len(r.formParam) != 0 || len(r.fileParam) != 0 || len(r.uploadFiles) != 0 {
 		hdr.Add(hdrContentTypeKey, formContentType)
 	}
 
+	if cookie := encodeCookies(r.cookieParam); cookie != "" {
+		hdr.Add(hdrCookieKey, cookie)
+	}
+
 	r.header = hdr
-	if c.cfg != nil {
-		return sign(r, c.cfg)
+	if c.signer != nil {
+		return sign(r, c.signer)
 	}
 
 	return nil
 }
 
-func sign(r *request, cfg *apiCfg.OpenapiConfig) error {
-	signer := signer.NewOpenApiSigner(cfg.AccessKey, cfg.SecretKey).
-		SetRequestId(utils.GetRandomString(32)).
-		SetHeader(r.header).
-		SetParam(r.queryParam)
+// encodeCookies renders cookies as a Cookie header value in a stable order
+func encodeCookies(cookies map[string]string) string {
+	names := make([]string, 0, len(cookies))
+	for name, value := range cookies {
+		if value == "" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, (&http.Cookie{Name: name, Value: cookies[name]}).String())
+	}
+	return strings.Join(pairs, "; ")
+}
+
+func sign(r *request, signer Signer) error {
+	req := &SigningRequest{
+		Method: r.method,
+		URL:    r.url,
+		Header: r.header,
+		Query:  r.queryParam,
+	}
 	if r.method == http.MethodPost || r.method == http.MethodPut {
-		if isFormType(r.header.Get(hdrContentTypeKey)) {
-			signer.SetBody(convertToURLValues(r.bodyParam))
+		if r.rawBody != nil {
+			req.Body = r.rawBody
 		} else {
-			body, err := json.Marshal(r.bodyParam)
+			contentType := r.header.Get(hdrContentTypeKey)
+			if isStringEmpty(contentType) {
+				contentType = detectContentType(r.bodyParam)
+			}
+			body, err := encodeRequestBody(contentType, r.bodyParam)
 			if err != nil {
 				return err
 			}
-			signer.SetBody(body)
+			req.Body = body
 		}
 	}
-	header, err := signer.Sign()
-	if err != nil {
-		return err
-	}
-	for k, v := range header {
-		r.header[k] = v
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	return nil
+	return signer.Sign(ctx, req)
 }
 
 // detectContentType method is used to figure out "request.Body" content type for request header
@@ -574,25 +2451,123 @@ func defaultRequestBodyBind(c *HttpClient, r *request) (contentType string, body
 	if !isPayloadSupported(r.method) {
 		return
 	}
-	var bodyBytes []byte
+	{{- if .Stdlib}}
+	if len(r.uploadFiles) != 0 || len(r.formParam) != 0 || len(r.fileParam) != 0 {
+	{{- else}}
+	if len(r.uploadFiles) != 0 {
+	{{- end}}
+		contentType, body = streamMultipart(r.formParam, r.fileParam, r.uploadFiles)
+		return contentType, body, nil
+	}
 	contentType = r.header.Get(hdrContentTypeKey)
+	if r.rawBody != nil {
+		if isStringEmpty(contentType) {
+			contentType = http.DetectContentType(r.rawBody)
+		}
+		return contentType, bytes.NewReader(r.rawBody), nil
+	}
 	if isStringEmpty(contentType) {
 		contentType = detectContentType(r.bodyParam)
 		r.header.Set(hdrContentTypeKey, contentType)
 	}
-	kind := reflect.Indirect(reflect.ValueOf(r.bodyParam)).Kind()
-	if isJSONType(contentType) &&
-		(kind == reflect.Struct || kind == reflect.Map || kind == reflect.Slice) {
-		bodyBytes, err = json.Marshal(r.bodyParam)
-	} else if isXMLType(contentType) && (kind == reflect.Struct) {
-		bodyBytes, err = xml.Marshal(r.bodyParam)
-	} else if isFormType(contentType) {
-		bodyBytes = convertToURLValues(r.bodyParam)
-    }
+	bodyBytes, err := encodeRequestBody(contentType, r.bodyParam)
 	if err != nil {
 		return
 	}
-	return contentType, strings.NewReader(string(bodyBytes)), nil
+	return contentType, bytes.NewReader(bodyBytes), nil
+}
+
+// streamMultipart writes the form through a pipe, so file contents are never held in memory
+func streamMultipart(formParam, fileParam map[string]string, files map[string]*UploadFile) (string, io.Reader) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		err := writeMultipart(mw, formParam, fileParam, files)
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return mw.FormDataContentType(), pr
+}
+
+func writeMultipart(mw *multipart.Writer, formParam, fileParam map[string]string, files map[string]*UploadFile) error {
+	for _, name := range sortedKeys(formParam) {
+		if err := mw.WriteField(name, formParam[name]); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(fileParam) {
+		path := fileParam[name]
+		if path == "" {
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = writeUploadFile(mw, name, &UploadFile{FileName: filepath.Base(path), Reader: f})
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeUploadFile(mw, name, files[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", "\"", "\\\"")
+
+func writeUploadFile(mw *multipart.Writer, name string, file *UploadFile) error {
+	contentType := file.ContentType
+	if isStringEmpty(contentType) {
+		contentType = "application/octet-stream"
+	}
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf("form-data; name=\"%s\"; filename=\"%s\"",
+		quoteEscaper.Replace(name), quoteEscaper.Replace(file.FileName)))
+	h.Set(hdrContentTypeKey, contentType)
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	total := file.Size
+	if total <= 0 {
+		total = -1
+	}
+	_, err = io.Copy(part, &progressReader{r: file.Reader, total: total, progress: file.Progress})
+	return err
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// encodeRequestBody marshals body with the codec of contentType,
+// content types without a codec such as multipart are left to createHTTPRequest
+func encodeRequestBody(contentType string, body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	c := codecFor(contentType)
+	if c == nil {
+		return nil, nil
+	}
+	return c.Marshal(body)
 }
 
 func isPayloadSupported(m string) bool {
@@ -601,6 +2576,36 @@ func isPayloadSupported(m string) bool {
 
 func createHTTPRequest(c *HttpClient, r *request) (err error) {
 	contentType, body, err := c.bindRequestBody(c, r)
+	{{- if .Stdlib}}
+	if err != nil {
+		return err
+	}
+	if !isStringEmpty(contentType) {
+		r.header.Set(hdrContentTypeKey, contentType)
+	}
+	if body == nil {
+		body = http.NoBody
+	}
+	r.rawRequest, err = http.NewRequestWithContext(r.ctx, r.method, r.url, body)
+	if err != nil {
+		return err
+	}
+	for key, values := range r.header {
+		for _, val := range values {
+			if val == "" {
+				continue
+			}
+			r.rawRequest.Header.Add(key, val)
+		}
+	}
+	if hostHeader := r.header.Get("Host"); hostHeader != "" {
+		r.rawRequest.Host = hostHeader
+	}
+	for _, option := range r.requestOptions {
+		option(r.rawRequest)
+	}
+	return nil
+	{{- else}}
 	if !isStringEmpty(contentType) {
 		r.header.Set(hdrContentTypeKey, contentType)
 	}
@@ -624,19 +2629,33 @@ func createHTTPRequest(c *HttpClient, r *request) (err error) {
 		r.rawRequest.SetOptions(r.requestOptions...)
 	}
 	return err
+	{{- end}}
 }
 
 func silently(_ ...interface{}) {}
 
 func defaultResponseResultDecider(res *response) error {
-	err := openapi.BindResponse(string(res.bodyByte), res.request.result)
+	if res.request.envelope != nil {
+		return decodeEnvelope(res, res.request.envelope)
+	}
+	if c := responseCodec(res); c != nil {
+		if _, ok := c.(jsonCodec); !ok {
+			return decodePayload(res, c)
+		}
+	}
+
+	err := bindResponse(string(res.bodyByte), res.request.result)
 	if err != nil {
+		// failures answered by a proxy or gateway, such as a 503 page, are told by their status code
+		if res.StatusCode() >= http.StatusBadRequest {
+			return decodePayload(res, jsonCodec{})
+		}
 		return err
 	}
 
-	openapiResp := res.request.result.(*openapi.Response)
+	openapiResp := res.request.result.(*OpenapiResponse)
 
-	if res.StatusCode() > 400 || openapiResp.ErrorCode != "" || openapiResp.Error != "" {
+	if res.StatusCode() >= http.StatusBadRequest || openapiResp.ErrorCode != "" || openapiResp.Error != "" {
 		if openapiResp.ParseStatusCode() == 800 {
 			return nil
 		}
@@ -646,10 +2665,14 @@ func defaultResponseResultDecider(res *response) error {
 			reason = openapiResp.Error
 		}
 
-		requestId := res.Header().Get("X-Request-Id")
+		requestId := responseRequestId(res)
+		// the detail is the returnObj of the failed response, the rest of the body is told by the StatusError
+		var body map[string]json.RawMessage
+		json.Unmarshal(res.bodyByte, &body)
+		decodeErrorDetail(res, jsonCodec{}, envelopeRaw(body, "returnObj"))
 
-		return &apiErr.StatusError{
-			ErrStatus: apiErr.Status{
+		return &StatusError{
+			ErrStatus: Status{
 				RequestId: requestId,
 				Code:    int32(openapiResp.ParseStatusCode()),
 				Reason:  reason,
@@ -661,6 +2684,291 @@ func defaultResponseResultDecider(res *response) error {
 	return nil
 }
 
+// decodePayload decodes media types that do not carry the openapi envelope,
+// the body is unmarshalled straight into the result and failures are told by the status code
+func decodePayload(res *response, c codec) error {
+	if res.StatusCode() >= http.StatusBadRequest {
+		decodeErrorDetail(res, c, res.bodyByte)
+		return &StatusError{
+			ErrStatus: Status{
+				RequestId: responseRequestId(res),
+				Code:      int32(res.StatusCode()),
+				Reason:    http.StatusText(res.StatusCode()),
+				Message:   string(res.bodyByte),
+			},
+		}
+	}
+
+	result := res.request.result
+	if openapiResp, ok := result.(*OpenapiResponse); ok {
+		result = openapiResp.ReturnObj
+	}
+	if result == nil || len(res.bodyByte) == 0 {
+		return nil
+	}
+	return c.Unmarshal(res.bodyByte, result)
+}
+
+// Envelope tells where a JSON response body keeps the result and the failure of a request
+type Envelope struct {
+	// None means the body is the result itself and failures are told by the status code
+	None bool
+	// CodeField holds SuccessCode in the body of the succeeded requests
+	CodeField   string
+	SuccessCode string
+	// DataPath is the dot separated path of the result, which is the whole body when it is empty
+	DataPath     string
+	ReasonField  string
+	MessageField string
+}
+
+// decodeEnvelope decodes the response by the envelope of its request, bodies of other media types
+// are decoded as they are
+func decodeEnvelope(res *response, env *Envelope) error {
+	c := responseCodec(res)
+	if c == nil {
+		c = jsonCodec{}
+	}
+	if _, ok := c.(jsonCodec); env.None || !ok {
+		return decodePayload(res, c)
+	}
+
+	var body map[string]json.RawMessage
+	if len(bytes.TrimSpace(res.bodyByte)) != 0 {
+		if err := json.Unmarshal(res.bodyByte, &body); err != nil {
+			if res.StatusCode() >= http.StatusBadRequest {
+				return decodePayload(res, c)
+			}
+			return err
+		}
+	}
+
+	code := envelopeValue(body, env.CodeField)
+	if res.StatusCode() >= http.StatusBadRequest || (code != "" && code != env.SuccessCode) {
+		reason := envelopeValue(body, env.ReasonField)
+		if reason == "" {
+			reason = code
+		}
+		if reason == "" {
+			reason = http.StatusText(res.StatusCode())
+		}
+		statusCode := int32(res.StatusCode())
+		if n, err := strconv.ParseInt(code, 10, 32); err == nil {
+			statusCode = int32(n)
+		}
+		decodeErrorDetail(res, c, res.bodyByte)
+		return &StatusError{
+			ErrStatus: Status{
+				RequestId: responseRequestId(res),
+				Code:      statusCode,
+				Reason:    reason,
+				Message:   envelopeValue(body, env.MessageField),
+			},
+		}
+	}
+
+	data := json.RawMessage(res.bodyByte)
+	if env.DataPath != "" {
+		data = envelopeRaw(body, env.DataPath)
+	}
+	result := res.request.result
+	if openapiResp, ok := result.(*OpenapiResponse); ok {
+		result = openapiResp.ReturnObj
+	}
+	if result == nil || len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return c.Unmarshal(data, result)
+}
+
+// envelopeRaw looks up the dot separated path in the JSON object body
+func envelopeRaw(body map[string]json.RawMessage, path string) json.RawMessage {
+	if path == "" {
+		return nil
+	}
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		raw, ok := body[key]
+		if !ok || i == len(keys)-1 {
+			return raw
+		}
+		body = nil
+		if json.Unmarshal(raw, &body) != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+// envelopeValue reads the scalar at the path of the body as a string, it is empty when the path is missing
+func envelopeValue(body map[string]json.RawMessage, path string) string {
+	raw := envelopeRaw(body, path)
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// decodeErrorDetail decodes the detail of a failed response into the error detail set by SetError,
+// which is dropped when the detail is missing, empty or does not decode so that the caller falls back
+// to the StatusError. Text bodies carry no details
+func decodeErrorDetail(res *response, c codec, detail []byte) {
+	if res.request.Error == nil {
+		return
+	}
+	switch string(bytes.TrimSpace(detail)) {
+	case "", "null", "{}":
+		res.request.Error = nil
+		return
+	}
+	if _, text := c.(textCodec); text || c.Unmarshal(detail, res.request.Error) != nil {
+		res.request.Error = nil
+		return
+	}
+	if v := reflect.ValueOf(res.request.Error); v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().IsZero() {
+		res.request.Error = nil
+	}
+}
+
+// responseCodec picks the codec from the response content type, falling back to the one of the request
+func responseCodec(res *response) codec {
+	if c := codecFor(res.Header().Get(hdrContentTypeKey)); c != nil {
+		return c
+	}
+	return codecFor(res.request.header.Get(hdrContentTypeKey))
+}
+
+// codec marshals request bodies and unmarshals response bodies of one media type
+type codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+func codecFor(contentType string) codec {
+	switch {
+	case isStringEmpty(contentType):
+		return nil
+	case isJSONType(contentType):
+		return jsonCodec{}
+	case isXMLType(contentType):
+		return xmlCodec{}
+	case isFormType(contentType):
+		return formCodec{}
+	case isProtobufType(contentType):
+		return protobufCodec{}
+	case isTextType(contentType):
+		return textCodec{}
+	}
+	return nil
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) Marshal(v interface{}) ([]byte, error) {
+	return xml.Marshal(v)
+}
+
+func (xmlCodec) Unmarshal(data []byte, v interface{}) error {
+	return xml.Unmarshal(data, v)
+}
+
+type formCodec struct{}
+
+func (formCodec) Marshal(v interface{}) ([]byte, error) {
+	if vals, ok := v.(url.Values); ok {
+		return []byte(vals.Encode()), nil
+	}
+	return convertToURLValues(v), nil
+}
+
+// Unmarshal maps the form fields onto v through their json names
+func (formCodec) Unmarshal(data []byte, v interface{}) error {
+	vals, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]interface{}, len(vals))
+	for k, vs := range vals {
+		if len(vs) == 1 {
+			fields[k] = vs[0]
+		} else {
+			fields[k] = vs
+		}
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protobuf codec can not marshal %T", v)
+	}
+	return proto.Marshal(m)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := allocResult(v).(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf codec can not unmarshal into %T", v)
+	}
+	return proto.Unmarshal(data, m)
+}
+
+type textCodec struct{}
+
+func (textCodec) Marshal(v interface{}) ([]byte, error) {
+	switch t := v.(type) {
+	case []byte:
+		return t, nil
+	case string:
+		return []byte(t), nil
+	}
+	return []byte(fmt.Sprint(v)), nil
+}
+
+// Unmarshal fills string and []byte results, other results are left to the raw response
+func (textCodec) Unmarshal(data []byte, v interface{}) error {
+	switch t := allocResult(v).(type) {
+	case *string:
+		*t = string(data)
+	case *[]byte:
+		*t = data
+	}
+	return nil
+}
+
+// allocResult allocates the value behind a pointer to a nil pointer and returns it
+func allocResult(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Ptr {
+		return v
+	}
+	if rv.Elem().IsNil() {
+		rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+	}
+	return rv.Elem().Interface()
+}
+
 // IsJSONType method is to check JSON content type or not
 func isJSONType(ct string) bool {
 	return jsonCheck.MatchString(ct)
@@ -675,6 +2983,14 @@ func isFormType(ct string) bool {
 	return formUrlCheck.MatchString(ct)
 }
 
+func isProtobufType(ct string) bool {
+	return protobufCheck.MatchString(ct)
+}
+
+func isTextType(ct string) bool {
+	return textCheck.MatchString(ct)
+}
+
 func convertToURLValues(bodyParam interface{}) []byte {
 	vals := url.Values{}
 	elem := reflect.ValueOf(bodyParam)
@@ -0,0 +1,122 @@
+package template
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// clientGoVersion is the client-go the client-go runtime is checked against when CRAFTER_CLIENT_GO is not set,
+// it is resolved by go get as the generated projects resolve theirs
+const clientGoVersion = "latest"
+
+// TestStdlibRuntime renders the stdlib runtime of the clients into a module and runs the tests
+// of testdata/runtime against it, the tests comparing it with client-go also run when
+// CRAFTER_CLIENT_GO is the directory of a client-go checkout
+func TestStdlibRuntime(t *testing.T) {
+	dir := t.TempDir()
+	goMod := runtimeGoMod(t)
+	var tags []string
+	if clientGo := os.Getenv("CRAFTER_CLIENT_GO"); clientGo != "" {
+		goMod += "\nrequire github.com/telecom-cloud/client-go v0.0.0\n\nreplace github.com/telecom-cloud/client-go => " + clientGo + "\n"
+		tags = []string{"-tags", "clientgo"}
+	}
+	writeFile(t, filepath.Join(dir, "go.mod"), goMod)
+	renderRuntime(t, dir, true)
+	tests, err := filepath.Glob(filepath.Join("testdata", "runtime", "*_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		src, err := os.ReadFile(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, filepath.Base(test)), string(src))
+	}
+
+	for _, args := range [][]string{{"vet"}, {"test"}} {
+		if out, err := goCommand(dir, append(append(args, tags...), ".")...); err != nil {
+			t.Fatalf("go %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+}
+
+// TestClientGoRuntime renders the default client-go runtime of the clients into a module and vets it
+// against client-go, the checkout at CRAFTER_CLIENT_GO or clientGoVersion
+func TestClientGoRuntime(t *testing.T) {
+	dir := t.TempDir()
+	goMod := runtimeGoMod(t)
+	clientGo := os.Getenv("CRAFTER_CLIENT_GO")
+	if clientGo != "" {
+		goMod += "\nrequire github.com/telecom-cloud/client-go v0.0.0\n\nreplace github.com/telecom-cloud/client-go => " + clientGo + "\n"
+	}
+	writeFile(t, filepath.Join(dir, "go.mod"), goMod)
+	renderRuntime(t, dir, false)
+	if clientGo == "" {
+		if out, err := goCommand(dir, "get", "github.com/telecom-cloud/client-go@"+clientGoVersion); err != nil {
+			t.Skipf("client-go %s is not available: %v\n%s", clientGoVersion, err, out)
+		}
+	}
+	if out, err := goCommand(dir, "vet", "."); err != nil {
+		t.Fatalf("go vet failed: %v\n%s", err, out)
+	}
+}
+
+// runtimeGoMod is the go.mod of the module the runtime is rendered into, it requires the protobuf of crafter
+func runtimeGoMod(t *testing.T) string {
+	t.Helper()
+	version, err := exec.Command("go", "list", "-m", "-f", "{{.Version}}", "google.golang.org/protobuf").Output()
+	if err != nil {
+		t.Skipf("go toolchain is not available: %v", err)
+	}
+	return "module example.com/runtime\n\ngo 1.22\n\nrequire google.golang.org/protobuf " + strings.TrimSpace(string(version)) + "\n"
+}
+
+// renderRuntime renders the runtime files of the clients into the package sdk at dir
+func renderRuntime(t *testing.T, dir string, stdlib bool) {
+	t.Helper()
+	goSum, err := os.ReadFile(filepath.Join("..", "..", "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "go.sum"), string(goSum))
+
+	runtime := "client-go"
+	if stdlib {
+		runtime = "stdlib"
+	}
+	data := map[string]interface{}{"PackageName": "sdk", "QueryEnumAsInt": false, "Stdlib": stdlib, "RuntimeVersion": runtime + "/1"}
+	for name, body := range map[string]string{
+		HttpClientTplName: httpClientTpl,
+		SignerTplName:     signerTpl,
+		RecorderTplName:   recorderTpl,
+	} {
+		tpl, err := template.New(name).Funcs(FuncMap).Parse(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out strings.Builder
+		if err = tpl.Execute(&out, data); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, name), out.String())
+	}
+}
+
+func goCommand(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	return cmd.CombinedOutput()
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -19,13 +19,11 @@ package {{.PackageName}}
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
-	"github.com/telecom-cloud/client-go/pkg/common/config"
-	"github.com/telecom-cloud/client-go/pkg/openapi"
-	"github.com/telecom-cloud/client-go/pkg/protocol"
-
 {{ range $k, $v := .Imports}}
 	{{$k}} "{{$v.Package}}"
 {{- end}}
@@ -33,14 +31,25 @@ import (
 
 // unused protection
 var (
+	_ = errors.New
 	_ = fmt.Formatter(nil)
+	_ = io.Writer(nil)
 )
 
 {{$Module := .ServiceName | TrimSuffix}}
 
 type {{$Module}}Client interface {
 	{{range $_, $MethodInfo := .ClientMethods}}
-		{{$MethodInfo.Name}}(context context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...config.RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *protocol.Response, err error)
+		{{$MethodInfo.Name}}(context context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *RawResponse, err error)
+		{{- if $MethodInfo.FileFields }}
+		{{$MethodInfo.Name}}WithFiles(context context.Context, req *{{$MethodInfo.RequestTypeName}}, files *{{$Module}}{{$MethodInfo.Name}}Files, reqOpt ...RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *RawResponse, err error)
+		{{- end }}
+		{{- if $MethodInfo.Download }}
+		{{$MethodInfo.Name}}Download(context context.Context, req *{{$MethodInfo.RequestTypeName}}, w io.Writer, progress ProgressFunc, reqOpt ...RequestOption) (rawResponse *RawResponse, err error)
+		{{- end }}
+		{{- if $MethodInfo.Lro }}
+		{{$MethodInfo.Name}}AndWait(context context.Context, req *{{$MethodInfo.RequestTypeName}}, opts *WaitOptions, reqOpt ...RequestOption) (resp *{{$MethodInfo.Lro.PollReturnTypeName}}, err error)
+		{{- end }}
 	{{end}}
 }
 
@@ -49,7 +58,10 @@ type {{$Module | ToLowerCamelCase}}Client struct {
 }
 
 func New{{$Module}}Client(hostUrl string, ops ...Option) ({{$Module}}Client, error) {
-	opts := GetOptions(append(ops, WithHostUrl(hostUrl))...)
+	opts := GetOptions(ops...)
+	if !opts.overrideHostUrl {
+		opts.hostUrl = hostUrl
+	}
 	cli, err := NewHttpClient(opts)
 	if err != nil {
 		return nil, err
@@ -60,8 +72,38 @@ func New{{$Module}}Client(hostUrl string, ops ...Option) ({{$Module}}Client, err
 }
 
 {{range $_, $MethodInfo := .ClientMethods}}
-func (s *{{$Module| ToLowerCamelCase}}Client) {{$MethodInfo.Name}}(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...config.RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *protocol.Response, err error) {
-	openapiResp := &openapi.Response{}
+{{- if $MethodInfo.ApiVersion }}
+// {{$Module}}{{$MethodInfo.Name}}ApiVersion is the api version requested by {{$Module}}Client.{{$MethodInfo.Name}}
+const {{$Module}}{{$MethodInfo.Name}}ApiVersion = "{{$MethodInfo.ApiVersion}}"
+{{ end }}
+{{- if $MethodInfo.FileFields }}
+// {{$Module}}{{$MethodInfo.Name}}Files are the files streamed by {{$Module}}Client.{{$MethodInfo.Name}}WithFiles
+type {{$Module}}{{$MethodInfo.Name}}Files struct {
+	{{- range $_, $File := $MethodInfo.FileFields }}
+	{{$File.GoName}} *UploadFile
+	{{- end }}
+}
+{{ end }}
+{{- if $MethodInfo.ErrorTypeName }}
+// {{$Module}}{{$MethodInfo.Name}}Error is returned by {{$Module}}Client.{{$MethodInfo.Name}} for the failures whose body decodes into the error details
+type {{$Module}}{{$MethodInfo.Name}}Error struct {
+	*StatusError
+	Detail *{{$MethodInfo.ErrorTypeName}}
+}
+
+func (e *{{$Module}}{{$MethodInfo.Name}}Error) Unwrap() error {
+	return e.StatusError
+}
+{{ end }}
+func (s *{{$Module| ToLowerCamelCase}}Client) {{$MethodInfo.Name}}(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *RawResponse, err error) {
+	{{- if $MethodInfo.FileFields }}
+	return s.{{$MethodInfo.Name}}WithFiles(ctx, req, nil, reqOpt...)
+}
+
+// {{$MethodInfo.Name}}WithFiles streams the non-nil files in place of the file paths set in req
+func (s *{{$Module| ToLowerCamelCase}}Client) {{$MethodInfo.Name}}WithFiles(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, files *{{$Module}}{{$MethodInfo.Name}}Files, reqOpt ...RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *RawResponse, err error) {
+	{{- end }}
+	openapiResp := &OpenapiResponse{}
 	{{- if $MethodInfo.DecodeCustomKey }}
 	resp = &{{$MethodInfo.ReturnTypeName}}{
 		{{$MethodInfo.DecodeCustomKey}}: make([]*{{$MethodInfo.ReturnTypePackage}}.{{$MethodInfo.DecodeCustomKey}}, 0),
@@ -70,14 +112,106 @@ func (s *{{$Module| ToLowerCamelCase}}Client) {{$MethodInfo.Name}}(ctx context.C
 	{{- else }}
 	openapiResp.ReturnObj = &resp
 	{{- end }}
-    {{if $MethodInfo.QueryParamsCode }}
+	if err = s.client.validate(req); err != nil {
+		return nil, nil, err
+	}
+	r := s.new{{$MethodInfo.Name}}Request(ctx, req, reqOpt...)
+	{{- if $MethodInfo.FileFields }}
+	if files != nil {
+		r.SetUploadFiles(map[string]*UploadFile{
+			{{- range $_, $File := $MethodInfo.FileFields }}
+			"{{$File.Name}}": files.{{$File.GoName}},
+			{{- end }}
+		})
+	}
+	{{- end }}
+	{{- if $MethodInfo.ErrorTypeName }}
+	detail := &{{$MethodInfo.ErrorTypeName}}{}
+	r.SetError(detail)
+	{{- end }}
+	ret, err := r.SetResult(openapiResp).
+		Execute({{if EqualFold $MethodInfo.HTTPMethod "Any"}}anyHttpMethod(reqOpt){{else}}http.Method{{ToHttpMethod $MethodInfo.HTTPMethod}}{{end}}, "{{$MethodInfo.Path}}")
+	if err != nil {
+		{{- if $MethodInfo.ErrorTypeName }}
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && r.Error != nil {
+			err = &{{$Module}}{{$MethodInfo.Name}}Error{StatusError: statusErr, Detail: detail}
+		}
+		{{- end }}
+		return nil, nil, r.interceptResponse(nil, err)
+	}
+	if err = r.interceptResponse(resp, nil); err != nil {
+		return nil, nil, err
+	}
+
+	rawResponse = ret.RawResponse
+	return resp, rawResponse, nil
+}
+{{if $MethodInfo.Download }}
+// {{$MethodInfo.Name}}Download streams the response body of {{$MethodInfo.Name}} into w, calling progress as it is written
+func (s *{{$Module| ToLowerCamelCase}}Client) {{$MethodInfo.Name}}Download(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, w io.Writer, progress ProgressFunc, reqOpt ...RequestOption) (rawResponse *RawResponse, err error) {
+	if err = s.client.validate(req); err != nil {
+		return nil, err
+	}
+	r := s.new{{$MethodInfo.Name}}Request(ctx, req, reqOpt...)
+	{{- if $MethodInfo.ErrorTypeName }}
+	detail := &{{$MethodInfo.ErrorTypeName}}{}
+	r.SetError(detail)
+	{{- end }}
+	ret, err := r.SetOutput(w, progress).
+		SetResult(&OpenapiResponse{}).
+		Execute({{if EqualFold $MethodInfo.HTTPMethod "Any"}}anyHttpMethod(reqOpt){{else}}http.Method{{ToHttpMethod $MethodInfo.HTTPMethod}}{{end}}, "{{$MethodInfo.Path}}")
+	{{- if $MethodInfo.ErrorTypeName }}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && r.Error != nil {
+		err = &{{$Module}}{{$MethodInfo.Name}}Error{StatusError: statusErr, Detail: detail}
+	}
+	{{- end }}
+	if err = r.interceptResponse(nil, err); err != nil || ret == nil {
+		return nil, err
+	}
+	return ret.RawResponse, nil
+}
+{{end}}
+{{- if $MethodInfo.Lro }}
+{{- $Lro := $MethodInfo.Lro }}
+// {{$MethodInfo.Name}}AndWait calls {{$MethodInfo.Name}} and polls {{$Lro.Poll}} with the job id it returns until the job
+// reaches a terminal status, resp is the last response of {{$Lro.Poll}}. Polls failing for a while are retried, see WaitOptions
+func (s *{{$Module| ToLowerCamelCase}}Client) {{$MethodInfo.Name}}AndWait(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, opts *WaitOptions, reqOpt ...RequestOption) (resp *{{$Lro.PollReturnTypeName}}, err error) {
+	started, _, err := s.{{$MethodInfo.Name}}(ctx, req, reqOpt...)
+	if err != nil {
+		return nil, err
+	}
+	jobId := started.{{$Lro.JobIdGetter}}
+	if jobId == {{$Lro.JobIdZero}} {
+		return nil, fmt.Errorf("{{$MethodInfo.Name}} returned no job id to poll {{$Lro.Poll}} with")
+	}
+	err = waitOperation(ctx, opts, fmt.Sprint(jobId), func(ctx context.Context) (string, error) {
+		var pollErr error
+		resp, _, pollErr = s.{{$Lro.Poll}}(ctx, &{{$Lro.PollRequestTypeName}}{ {{- $Lro.PollJobId}}: {{if $Lro.PollJobRef}}&{{end}}jobId}, reqOpt...)
+		return fmt.Sprint(resp.{{$Lro.Status}}), pollErr
+	}, []string{ {{- range $i, $s := $Lro.Success}}{{if $i}}, {{end}}{{printf "%q" $s}}{{end -}} }, []string{ {{- range $i, $s := $Lro.Failure}}{{if $i}}, {{end}}{{printf "%q" $s}}{{end -}} })
+	return resp, err
+}
+{{end}}
+func (s *{{$Module| ToLowerCamelCase}}Client) new{{$MethodInfo.Name}}Request(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...RequestOption) *request {
+    {{- if $MethodInfo.QueryParamsCode }}
 	queryParams := map[string]interface{}{
 		{{$MethodInfo.QueryParamsCode}}
 	}
 	OptimizeQueryParams(queryParams)
     {{- end }}
-	ret, err := s.client.R().
+	return s.client.R().
 		SetContext(ctx).
+		SetCallInfo(&CallInfo{Service: "{{$Module}}", Method: "{{$MethodInfo.Name}}", Route: "{{$MethodInfo.Path}}"}, req).
+		{{if $MethodInfo.BaseUrl }}
+		SetHostUrl("{{$MethodInfo.BaseUrl}}").
+		{{- end }}
+		{{if $MethodInfo.QueryStyleCode }}
+		SetQueryStyles(map[string]string{
+			{{$MethodInfo.QueryStyleCode}}
+		}).
+		{{- end }}
 		{{if $MethodInfo.QueryParamsCode }}
 		SetQueryParams(queryParams).
 		{{- end }}
@@ -91,6 +225,20 @@ func (s *{{$Module| ToLowerCamelCase}}Client) {{$MethodInfo.Name}}(ctx context.C
 			{{$MethodInfo.HeaderParamsCode}}
 		}).
 		{{- end }}
+		{{if $MethodInfo.ContentType }}
+		SetContentType("{{$MethodInfo.ContentType}}").
+		{{- end }}
+		{{if $MethodInfo.Idempotency }}
+		SetIdempotencyHeader("{{$MethodInfo.Idempotency}}").
+		{{- end }}
+		{{if $MethodInfo.EnvelopeCode }}
+		SetEnvelope(&Envelope{ {{- $MethodInfo.EnvelopeCode -}} }).
+		{{- end }}
+		{{if $MethodInfo.CookieParamsCode }}
+		SetCookies(map[string]string{
+			{{$MethodInfo.CookieParamsCode}}
+		}).
+		{{- end }}
 		{{if $MethodInfo.FormValueCode }}
 		SetFormParams(map[string]string{
 			{{$MethodInfo.FormValueCode}}
@@ -102,30 +250,39 @@ func (s *{{$Module| ToLowerCamelCase}}Client) {{$MethodInfo.Name}}(ctx context.C
 		}).
 		{{- end }}
 		{{$MethodInfo.BodyParamsCode}}
-		SetRequestOption(reqOpt...).
-		SetResult(openapiResp).
-		Execute(http.Method{{if EqualFold $MethodInfo.HTTPMethod "Any"}}Post{{else}}{{ToHttpMethod $MethodInfo.HTTPMethod}}{{end}}, "{{$MethodInfo.Path}}")
-	if err != nil {
-		return nil, nil, err
-	}
-    
-	rawResponse = ret.RawResponse
-	return resp, rawResponse, nil
+		SetRequestOption(reqOpt...)
 }
 {{end}}
 
-var default{{$Module}}Client, _ = New{{$Module}}Client(baseDomain)
+var default{{$Module}}Client, _ = New{{$Module}}Client(baseDomain, groupOptions(baseDomain, nil)...)
 
+// ConfigDefault{{$Module}}Client recreates the client of the functions of {{$Module}} with ops,
+// which follow the defaults of the service group as they do in NewClientSet
 func ConfigDefault{{$Module}}Client(ops ...Option) (err error) {
-	default{{$Module}}Client, err = New{{$Module}}Client(baseDomain, ops...)
+	default{{$Module}}Client, err = New{{$Module}}Client(baseDomain, groupOptions(baseDomain, ops)...)
 	return
 }
 
 {{range $_, $MethodInfo := .ClientMethods}}
-func {{$MethodInfo.Name}}(context context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...config.RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *protocol.Response, err error) {
+func {{$MethodInfo.Name}}(context context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *RawResponse, err error) {
 	return default{{$Module}}Client.{{$MethodInfo.Name}}(context, req, reqOpt...)
 }
+{{if $MethodInfo.FileFields }}
+func {{$MethodInfo.Name}}WithFiles(context context.Context, req *{{$MethodInfo.RequestTypeName}}, files *{{$Module}}{{$MethodInfo.Name}}Files, reqOpt ...RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *RawResponse, err error) {
+	return default{{$Module}}Client.{{$MethodInfo.Name}}WithFiles(context, req, files, reqOpt...)
+}
 {{end}}
+{{- if $MethodInfo.Download }}
+func {{$MethodInfo.Name}}Download(context context.Context, req *{{$MethodInfo.RequestTypeName}}, w io.Writer, progress ProgressFunc, reqOpt ...RequestOption) (rawResponse *RawResponse, err error) {
+	return default{{$Module}}Client.{{$MethodInfo.Name}}Download(context, req, w, progress, reqOpt...)
+}
+{{end}}
+{{- if $MethodInfo.Lro }}
+func {{$MethodInfo.Name}}AndWait(context context.Context, req *{{$MethodInfo.RequestTypeName}}, opts *WaitOptions, reqOpt ...RequestOption) (resp *{{$MethodInfo.Lro.PollReturnTypeName}}, err error) {
+	return default{{$Module}}Client.{{$MethodInfo.Name}}AndWait(context, req, opts, reqOpt...)
+}
+{{end}}
+{{- end}}
 `
 
 var idlGroupClientTpl = `// Licensed under the Apache License, Version 2.0 (the "License");
@@ -146,48 +303,112 @@ var idlGroupClientTpl = `// Licensed under the Apache License, Version 2.0 (the
 package {{.ServiceGroup}}
 
 import (
-	"crypto/tls"
-
-	cli "github.com/telecom-cloud/client-go/pkg/client"
+	"fmt"
+	"sync"
 )
 
 var baseDomain = "{{.BaseDomain}}"
 
+// DefaultTLSConfig is the TLS configuration every ClientSet starts from, servers are verified
+// against the system roots by default
+var DefaultTLSConfig = TLSConfig{}
+
+// endpoints are the hosts the clients of the set fail over between, in order of preference
+var endpoints = []string{
+	{{- range .Endpoints }}
+	"{{.}}",
+	{{- end }}
+}
+
+// Regions maps the region ids of the service group to their hosts, see WithRegion
+var Regions = map[string]string{
+	{{- range $region, $host := .Regions }}
+	"{{$region}}": "{{$host}}",
+	{{- end }}
+}
+
 type ClientSet interface {
 	{{- range .Clients }}
 	{{.}}() {{.}}Client
 	{{- end }}
 }
 
+// clientSetServices are the names WithServiceOption accepts
+var clientSetServices = map[string]bool{
+	{{- range .Clients }}
+	"{{.}}": true,
+	{{- end }}
+}
+
+// clientSet shares one HttpClient between its clients, which are created on first use
 type clientSet struct {
+	httpClient         *HttpClient
+	serviceHttpClients map[string]*HttpClient
 	{{- range .Clients }}
-	{{.|ToLowerCamelCase}}Cli {{.}}Client
+
+	{{.|ToLowerCamelCase}}Once sync.Once
+	{{.|ToLowerCamelCase}}Cli  {{.}}Client
 	{{- end }}
 }
 
-func NewClientSet(baseDomain string, options ...Option) (ClientSet, error) {
+// groupOptions puts the defaults of the service group before options, the clients of a set and
+// the default clients of the services are all created with them
+func groupOptions(baseDomain string, options []Option) []Option {
 	defaultOpt := []Option{
-		WithClientOption(cli.WithTLSConfig(&tls.Config{
-			InsecureSkipVerify: true,
-		})),
+		WithTLS(DefaultTLSConfig),
+		WithRegionHosts(Regions),
 	}
-	options = append(defaultOpt, options...)
-	{{- range .Clients }}
-	{{.|ToLowerCamelCase}}Cli, err := New{{.}}Client(baseDomain, options...)
+	if len(endpoints) != 0 {
+		// baseDomain is tried first, the annotated endpoints are failed over to
+		failover := []string{baseDomain}
+		for _, endpoint := range endpoints {
+			if endpoint != baseDomain {
+				failover = append(failover, endpoint)
+			}
+		}
+		defaultOpt = append(defaultOpt, WithEndpoints(failover...))
+	}
+	return append(defaultOpt, options...)
+}
+
+func NewClientSet(baseDomain string, options ...Option) (ClientSet, error) {
+	options = groupOptions(baseDomain, options)
+	opts := GetOptions(options...)
+	if !opts.overrideHostUrl {
+		opts.hostUrl = baseDomain
+	}
+	httpClient, err := NewHttpClient(opts)
 	if err != nil {
 		return nil, err
 	}
-	{{- end }}
 
-	return &clientSet{
-		{{- range .Clients }}
-		{{.|ToLowerCamelCase}}Cli: {{.|ToLowerCamelCase}}Cli,
-		{{- end }}
-	}, nil
+	cs := &clientSet{
+		httpClient:         httpClient,
+		serviceHttpClients: make(map[string]*HttpClient, len(opts.serviceOptions)),
+	}
+	for service, serviceOptions := range opts.serviceOptions {
+		if !clientSetServices[service] {
+			return nil, fmt.Errorf("unknown service %s of WithServiceOption", service)
+		}
+		cs.serviceHttpClients[service], err = newServiceHttpClient(httpClient, baseDomain, options, serviceOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cs, nil
 }
 
+func (cs *clientSet) httpClientOf(service string) *HttpClient {
+	if c, ok := cs.serviceHttpClients[service]; ok {
+		return c
+	}
+	return cs.httpClient
+}
 {{range .Clients }}
 func (cs *clientSet) {{.}}() {{.}}Client {
+	cs.{{.|ToLowerCamelCase}}Once.Do(func() {
+		cs.{{.|ToLowerCamelCase}}Cli = &{{.|ToLowerCamelCase}}Client{client: cs.httpClientOf("{{.}}")}
+	})
 	return cs.{{.|ToLowerCamelCase}}Cli
 }
 {{end }}
@@ -0,0 +1,193 @@
+package template
+
+var idlFakeClientTpl = `// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Telecom Cloud SDK Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+package fake
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	{{.GroupAlias}} "{{.ServiceGroupImport}}"
+{{- range $k, $v := .Imports}}
+	{{$k}} "{{$v.Package}}"
+{{- end}}
+)
+
+{{$Module := .ServiceName | TrimSuffix}}
+{{- $Group := .GroupAlias}}
+
+var _ {{$Group}}.{{$Module}}Client = (*Fake{{$Module}}Client)(nil)
+
+// Fake{{$Module}}Client is a stand-in of {{$Group}}.{{$Module}}Client for unit tests,
+// each method calls its stub when set, otherwise it returns the values given to its Returns method
+type Fake{{$Module}}Client struct {
+	mu sync.Mutex
+	{{range $_, $MethodInfo := .ClientMethods}}
+	{{$MethodInfo.Name}}Stub func(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...{{$Group}}.RequestOption) (*{{$MethodInfo.ReturnTypeName}}, *{{$Group}}.RawResponse, error)
+	{{$MethodInfo.Name | ToLowerCamelCase}}Calls   []Fake{{$Module}}{{$MethodInfo.Name}}Call
+	{{$MethodInfo.Name | ToLowerCamelCase}}Returns struct {
+		resp        *{{$MethodInfo.ReturnTypeName}}
+		rawResponse *{{$Group}}.RawResponse
+		err         error
+	}
+	{{- if $MethodInfo.Download }}
+	{{$MethodInfo.Name}}DownloadStub func(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, w io.Writer, progress {{$Group}}.ProgressFunc, reqOpt ...{{$Group}}.RequestOption) (*{{$Group}}.RawResponse, error)
+	{{- end }}
+	{{- if $MethodInfo.Lro }}
+	{{$MethodInfo.Name}}AndWaitStub func(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, opts *{{$Group}}.WaitOptions, reqOpt ...{{$Group}}.RequestOption) (*{{$MethodInfo.Lro.PollReturnTypeName}}, error)
+	{{- end }}
+	{{end}}
+}
+
+// unused protection
+var _ = io.Writer(nil)
+
+{{range $_, $MethodInfo := .ClientMethods}}
+{{- $Calls := printf "%sCalls" ($MethodInfo.Name | ToLowerCamelCase)}}
+{{- $Returns := printf "%sReturns" ($MethodInfo.Name | ToLowerCamelCase)}}
+// Fake{{$Module}}{{$MethodInfo.Name}}Call records the arguments of one {{$MethodInfo.Name}} call
+type Fake{{$Module}}{{$MethodInfo.Name}}Call struct {
+	Ctx    context.Context
+	Req    *{{$MethodInfo.RequestTypeName}}
+	ReqOpt []{{$Group}}.RequestOption
+}
+
+func (f *Fake{{$Module}}Client) {{$MethodInfo.Name}}(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, reqOpt ...{{$Group}}.RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *{{$Group}}.RawResponse, err error) {
+	f.mu.Lock()
+	f.{{$Calls}} = append(f.{{$Calls}}, Fake{{$Module}}{{$MethodInfo.Name}}Call{Ctx: ctx, Req: req, ReqOpt: reqOpt})
+	stub := f.{{$MethodInfo.Name}}Stub
+	returns := f.{{$Returns}}
+	f.mu.Unlock()
+	if stub != nil {
+		return stub(ctx, req, reqOpt...)
+	}
+	return returns.resp, returns.rawResponse, returns.err
+}
+
+// {{$MethodInfo.Name}}CallCount returns how many times {{$MethodInfo.Name}} has been called
+func (f *Fake{{$Module}}Client) {{$MethodInfo.Name}}CallCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.{{$Calls}})
+}
+
+// {{$MethodInfo.Name}}ArgsForCall returns the arguments of the i-th {{$MethodInfo.Name}} call
+func (f *Fake{{$Module}}Client) {{$MethodInfo.Name}}ArgsForCall(i int) Fake{{$Module}}{{$MethodInfo.Name}}Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.{{$Calls}}[i]
+}
+
+// {{$MethodInfo.Name}}Returns sets the values returned by {{$MethodInfo.Name}} when no stub is set
+func (f *Fake{{$Module}}Client) {{$MethodInfo.Name}}Returns(resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *{{$Group}}.RawResponse, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.{{$Returns}}.resp = resp
+	f.{{$Returns}}.rawResponse = rawResponse
+	f.{{$Returns}}.err = err
+}
+{{- if $MethodInfo.FileFields }}
+
+// {{$MethodInfo.Name}}WithFiles is recorded and answered as a call of {{$MethodInfo.Name}}
+func (f *Fake{{$Module}}Client) {{$MethodInfo.Name}}WithFiles(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, files *{{$Group}}.{{$Module}}{{$MethodInfo.Name}}Files, reqOpt ...{{$Group}}.RequestOption) (resp *{{$MethodInfo.ReturnTypeName}}, rawResponse *{{$Group}}.RawResponse, err error) {
+	return f.{{$MethodInfo.Name}}(ctx, req, reqOpt...)
+}
+{{- end }}
+{{- if $MethodInfo.Download }}
+
+// {{$MethodInfo.Name}}Download calls {{$MethodInfo.Name}}DownloadStub when set,
+// otherwise it is recorded and answered as a call of {{$MethodInfo.Name}}
+func (f *Fake{{$Module}}Client) {{$MethodInfo.Name}}Download(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, w io.Writer, progress {{$Group}}.ProgressFunc, reqOpt ...{{$Group}}.RequestOption) (rawResponse *{{$Group}}.RawResponse, err error) {
+	f.mu.Lock()
+	stub := f.{{$MethodInfo.Name}}DownloadStub
+	f.mu.Unlock()
+	if stub != nil {
+		return stub(ctx, req, w, progress, reqOpt...)
+	}
+	_, rawResponse, err = f.{{$MethodInfo.Name}}(ctx, req, reqOpt...)
+	return rawResponse, err
+}
+{{- end }}
+{{- if $MethodInfo.Lro }}
+{{- $Lro := $MethodInfo.Lro }}
+
+// {{$MethodInfo.Name}}AndWait calls {{$MethodInfo.Name}}AndWaitStub when set, otherwise it is recorded and answered
+// as a call of {{$MethodInfo.Name}} followed by a single call of {{$Lro.Poll}}
+func (f *Fake{{$Module}}Client) {{$MethodInfo.Name}}AndWait(ctx context.Context, req *{{$MethodInfo.RequestTypeName}}, opts *{{$Group}}.WaitOptions, reqOpt ...{{$Group}}.RequestOption) (resp *{{$Lro.PollReturnTypeName}}, err error) {
+	f.mu.Lock()
+	stub := f.{{$MethodInfo.Name}}AndWaitStub
+	f.mu.Unlock()
+	if stub != nil {
+		return stub(ctx, req, opts, reqOpt...)
+	}
+	started, _, err := f.{{$MethodInfo.Name}}(ctx, req, reqOpt...)
+	if err != nil {
+		return nil, err
+	}
+	jobId := started.{{$Lro.JobIdGetter}}
+	resp, _, err = f.{{$Lro.Poll}}(ctx, &{{$Lro.PollRequestTypeName}}{ {{- $Lro.PollJobId}}: {{if $Lro.PollJobRef}}&{{end}}jobId}, reqOpt...)
+	return resp, err
+}
+{{- end }}
+{{end}}
+`
+
+var idlFakeGroupClientTpl = `// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Telecom Cloud SDK Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+package fake
+
+import (
+	{{.ServiceGroup}} "{{.ServiceGroupImport}}"
+)
+
+var _ {{.ServiceGroup}}.ClientSet = (*FakeClientSet)(nil)
+
+// FakeClientSet is a stand-in of {{.ServiceGroup}}.ClientSet returning the fake clients
+type FakeClientSet struct {
+	{{- range .Clients }}
+	{{.}}Fake *Fake{{.}}Client
+	{{- end }}
+}
+
+func NewFakeClientSet() *FakeClientSet {
+	return &FakeClientSet{
+		{{- range .Clients }}
+		{{.}}Fake: &Fake{{.}}Client{},
+		{{- end }}
+	}
+}
+
+{{range .Clients }}
+func (cs *FakeClientSet) {{.}}() {{$.ServiceGroup}}.{{.}}Client {
+	return cs.{{.}}Fake
+}
+{{end }}
+`
@@ -5,18 +5,27 @@ var (
 	MiddlewareSingleTplName = "middleware_single.go"
 	ModelTplName            = "model.go"
 	HttpClientTplName       = "httpclient.go" // underlying client for client command
+	SignerTplName           = "signer.go"     // request signers of the underlying client
+	RecorderTplName         = "recorder.go"   // record/replay doer of the underlying client
 	ErrorTplName            = "errors.go"
 	IdlClientTplName        = "idl_client.go" // client of service for quick call
 	IdlGroupClientTplName   = "idl_group_client.go"
+	// fakes of the clients for unit tests
+	IdlFakeClientTplName      = "idl_fake_client.go"
+	IdlFakeGroupClientTplName = "idl_fake_group_client.go"
 )
 
 var templateNameSet = map[string]string{
-	MiddlewareTplName:       MiddlewareTplName,
-	MiddlewareSingleTplName: MiddlewareSingleTplName,
-	ModelTplName:            ModelTplName,
-	IdlClientTplName:        IdlClientTplName,
-	HttpClientTplName:       HttpClientTplName,
-	IdlGroupClientTplName:   IdlGroupClientTplName,
+	MiddlewareTplName:         MiddlewareTplName,
+	MiddlewareSingleTplName:   MiddlewareSingleTplName,
+	ModelTplName:              ModelTplName,
+	IdlClientTplName:          IdlClientTplName,
+	HttpClientTplName:         HttpClientTplName,
+	SignerTplName:             SignerTplName,
+	RecorderTplName:           RecorderTplName,
+	IdlGroupClientTplName:     IdlGroupClientTplName,
+	IdlFakeClientTplName:      IdlFakeClientTplName,
+	IdlFakeGroupClientTplName: IdlFakeGroupClientTplName,
 }
 
 func IsDefaultPackageTpl(name string) bool {
@@ -40,6 +49,16 @@ var DefaultPkgConfig = Config{
 			Delims: [2]string{"{{", "}}"},
 			Body:   httpClientTpl,
 		},
+		{
+			Path:   defaultClientDir + sp + SignerTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body:   signerTpl,
+		},
+		{
+			Path:   defaultClientDir + sp + RecorderTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body:   recorderTpl,
+		},
 		{
 			Path:   defaultClientDir + sp + IdlGroupClientTplName,
 			Delims: [2]string{"{{", "}}"},
@@ -50,6 +69,16 @@ var DefaultPkgConfig = Config{
 			Delims: [2]string{"{{", "}}"},
 			Body:   idlClientTpl,
 		},
+		{
+			Path:   defaultClientDir + sp + IdlFakeClientTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body:   idlFakeClientTpl,
+		},
+		{
+			Path:   defaultClientDir + sp + IdlFakeGroupClientTplName,
+			Delims: [2]string{"{{", "}}"},
+			Body:   idlFakeGroupClientTpl,
+		},
 		//{
 		//	Path:   defaultClientDir + sp + ErrorTplName,
 		//	Delims: [2]string{"{{", "}}"},
@@ -0,0 +1,336 @@
+package template
+
+var recorderTpl = `// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Telecom Cloud SDK Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"compress/gzip"
+	{{- if not .Stdlib}}
+	"context"
+	{{- end}}
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+	{{if not .Stdlib}}
+	cli "github.com/telecom-cloud/client-go/pkg/client"
+	"github.com/telecom-cloud/client-go/pkg/protocol"
+	{{- end}}
+)
+
+// RecordMode tells a Recorder whether to record the exchanges or to replay them
+type RecordMode int
+
+const (
+	// ModeReplay serves the requests from the fixtures without network access
+	ModeReplay RecordMode = iota
+	// ModeRecord sends the requests through the doer and writes the exchanges as fixtures
+	ModeRecord
+)
+
+// EnvClientRecord selects ModeRecord for RecordModeFromEnv when set to "record"
+const EnvClientRecord = "CRAFTER_CLIENT_RECORD"
+
+// RecordModeFromEnv is ModeRecord when EnvClientRecord is "record", and ModeReplay otherwise,
+// so that tests replay the fixtures unless asked to record them again
+func RecordModeFromEnv() RecordMode {
+	if strings.EqualFold(os.Getenv(EnvClientRecord), "record") {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Recorder is a Doer for WithClient which records the exchanges of a client as fixture files in a directory,
+// or replays them. Exchanges are matched on the method, the route, the query and the normalized body,
+// the repeated ones are replayed in the order they were recorded. Credentials and signatures are not recorded
+type Recorder struct {
+	dir  string
+	mode RecordMode
+	doer Doer
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// NewRecorder returns a Recorder of the fixtures in dir, doer sends the requests in ModeRecord
+// and defaults to a client verifying servers against the system roots
+func NewRecorder(dir string, mode RecordMode, doer Doer) (*Recorder, error) {
+	if mode == ModeRecord {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		if doer == nil {
+			{{- if .Stdlib}}
+			doer = &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+			{{- else}}
+			c, err := cli.NewClient(cli.WithResponseBodyStream(true))
+			if err != nil {
+				return nil, err
+			}
+			doer = c
+			{{- end}}
+		}
+	}
+	return &Recorder{dir: dir, mode: mode, doer: doer, calls: make(map[string]int)}, nil
+}
+{{if .Stdlib}}
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	name, exchange := r.fixture(req.Method, req.URL, req.Header, body)
+
+	if r.mode == ModeReplay {
+		f, err := r.replay(name, exchange)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", f.Response.Status, http.StatusText(f.Response.Status)),
+			StatusCode:    f.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        f.Response.Header,
+			Body:          io.NopCloser(bytes.NewReader(f.Response.body())),
+			ContentLength: int64(len(f.Response.body())),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := r.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, r.record(name, exchange, resp.StatusCode, resp.Header, respBody)
+}
+{{else}}
+func (r *Recorder) Do(ctx context.Context, req *protocol.Request, resp *protocol.Response) error {
+	u, err := url.Parse(string(req.URI().FullURI()))
+	if err != nil {
+		return err
+	}
+	header := make(http.Header)
+	req.Header.VisitAll(func(k, v []byte) {
+		header.Add(string(k), string(v))
+	})
+	name, exchange := r.fixture(string(req.Method()), u, header, req.Body())
+
+	if r.mode == ModeReplay {
+		f, err := r.replay(name, exchange)
+		if err != nil {
+			return err
+		}
+		resp.SetStatusCode(f.Response.Status)
+		for k, values := range f.Response.Header {
+			for _, v := range values {
+				resp.Header.Add(k, v)
+			}
+		}
+		resp.SetBody(f.Response.body())
+		return nil
+	}
+
+	if err = r.doer.Do(ctx, req, resp); err != nil {
+		return err
+	}
+	respBody, err := resp.BodyE()
+	if err != nil {
+		return err
+	}
+	respHeader := make(http.Header)
+	resp.Header.VisitAll(func(k, v []byte) {
+		respHeader.Add(string(k), string(v))
+	})
+	return r.record(name, exchange, resp.StatusCode(), respHeader, respBody)
+}
+{{end}}
+// fixtureFile is an exchange recorded by a Recorder
+type fixtureFile struct {
+	Request  fixtureRequest  ` + "`" + `json:"request"` + "`" + `
+	Response fixtureResponse ` + "`" + `json:"response"` + "`" + `
+}
+
+type fixtureRequest struct {
+	Method string      ` + "`" + `json:"method"` + "`" + `
+	Route  string      ` + "`" + `json:"route"` + "`" + `
+	Query  string      ` + "`" + `json:"query,omitempty"` + "`" + `
+	Header http.Header ` + "`" + `json:"header,omitempty"` + "`" + `
+	Body   string      ` + "`" + `json:"body,omitempty"` + "`" + `
+}
+
+type fixtureResponse struct {
+	Status int         ` + "`" + `json:"status"` + "`" + `
+	Header http.Header ` + "`" + `json:"header,omitempty"` + "`" + `
+	Body   string      ` + "`" + `json:"body,omitempty"` + "`" + `
+	// Base64 tells the body is base64 encoded, as it is not UTF-8 text
+	Base64 bool ` + "`" + `json:"base64,omitempty"` + "`" + `
+}
+
+func (f fixtureResponse) body() []byte {
+	if f.Base64 {
+		body, _ := base64.StdEncoding.DecodeString(f.Body)
+		return body
+	}
+	return []byte(f.Body)
+}
+
+var unsafeFixtureName = regexp.MustCompile("[^A-Za-z0-9]+")
+
+// fixture names the fixture file of the exchange of a request, which is numbered by the calls of the same request
+func (r *Recorder) fixture(method string, u *url.URL, header http.Header, body []byte) (string, fixtureRequest) {
+	query := u.Query()
+	for k := range query {
+		if isSensitiveHeader(k) {
+			query.Del(k)
+		}
+	}
+	exchange := fixtureRequest{
+		Method: method,
+		Route:  u.Path,
+		Query:  query.Encode(),
+		Header: recordedHeader(header),
+		Body:   normalizeBody(header.Get(hdrContentTypeKey), body),
+	}
+	// the ids differ on every run, so fixtures recorded again would change for nothing
+	exchange.Header.Del(HeaderRequestId)
+	sum := sha256.Sum256([]byte(exchange.Method + " " + exchange.Route + "?" + exchange.Query + "\n" + exchange.Body))
+	key := hex.EncodeToString(sum[:4])
+
+	r.mu.Lock()
+	r.calls[key]++
+	n := r.calls[key]
+	r.mu.Unlock()
+
+	route := strings.Trim(unsafeFixtureName.ReplaceAllString(u.Path, "_"), "_")
+	if len(route) > 60 {
+		route = route[:60]
+	}
+	return fmt.Sprintf("%s_%s_%s_%d.json", strings.ToLower(method), route, key, n), exchange
+}
+
+// replay reads the fixture of name, falling back to the last one recorded of the same request
+// when it is called more times than it was recorded
+func (r *Recorder) replay(name string, exchange fixtureRequest) (*fixtureFile, error) {
+	prefix, n := name, 0
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		prefix = name[:i]
+		fmt.Sscanf(name[i+1:], "%d.json", &n)
+	}
+	for ; n > 0; n-- {
+		data, err := os.ReadFile(filepath.Join(r.dir, fmt.Sprintf("%s_%d.json", prefix, n)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		f := &fixtureFile{}
+		if err = json.Unmarshal(data, f); err != nil {
+			return nil, fmt.Errorf("read fixture %s failed: %w", name, err)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("no fixture of %s %s in %s, record it with %s=record", exchange.Method, exchange.Route, r.dir, EnvClientRecord)
+}
+
+func (r *Recorder) record(name string, exchange fixtureRequest, status int, header http.Header, body []byte) error {
+	header = recordedHeader(header)
+	if strings.EqualFold(header.Get(hdrContentEncodingKey), "gzip") {
+		if gz, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			if plain, err := io.ReadAll(gz); err == nil {
+				body = plain
+				header.Del(hdrContentEncodingKey)
+			}
+		}
+	}
+	header.Del("Content-Length")
+	f := fixtureFile{
+		Request:  exchange,
+		Response: fixtureResponse{Status: status, Header: header, Body: string(body)},
+	}
+	if !utf8.Valid(body) {
+		f.Response.Body, f.Response.Base64 = base64.StdEncoding.EncodeToString(body), true
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.dir, name), append(data, '\n'), 0o644)
+}
+
+// recordedHeader drops the credentials, the signatures and the headers signed along with them
+func recordedHeader(header http.Header) http.Header {
+	recorded := make(http.Header, len(header))
+	for k, v := range header {
+		name := strings.ToLower(k)
+		if isSensitiveHeader(k) || strings.HasPrefix(name, "eop-") || strings.HasPrefix(name, "ctyun-eop-") {
+			continue
+		}
+		recorded[http.CanonicalHeaderKey(k)] = v
+	}
+	return recorded
+}
+
+// normalizeBody makes the bodies of the same request compare equal, JSON is re-encoded with sorted keys
+// and the random multipart boundary is replaced
+func normalizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if boundary := params["boundary"]; strings.HasPrefix(mediaType, "multipart/") && boundary != "" {
+		return strings.ReplaceAll(string(body), boundary, "BOUNDARY")
+	}
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if decoder.Decode(&v) == nil {
+		if normalized, err := json.Marshal(v); err == nil {
+			return string(normalized)
+		}
+	}
+	if !utf8.Valid(body) {
+		return base64.StdEncoding.EncodeToString(body)
+	}
+	return string(body)
+}
+`
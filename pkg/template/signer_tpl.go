@@ -0,0 +1,355 @@
+package template
+
+var signerTpl = `// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Telecom Cloud SDK Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+package {{.PackageName}}
+
+import (
+	"bufio"
+	"context"
+	{{- if .Stdlib}}
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	{{- end}}
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	{{if not .Stdlib}}
+	"github.com/telecom-cloud/client-go/pkg/common/utils"
+	"github.com/telecom-cloud/client-go/pkg/openapi/signer"
+	{{- end}}
+)
+
+const (
+	EnvAccessKey       = "CTYUN_ACCESS_KEY"
+	EnvSecretKey       = "CTYUN_SECRET_KEY"
+	EnvToken           = "CTYUN_TOKEN"
+	EnvProfile         = "CTYUN_PROFILE"
+	EnvCredentialsFile = "CTYUN_CREDENTIALS_FILE"
+
+	defaultProfile = "default"
+	// credentials are refreshed this long before they expire
+	expiryWindow = time.Minute
+)
+
+// ErrNoCredentials is returned by a CredentialProvider which has no credentials to offer,
+// ChainCredentials moves on to the next provider on it
+var ErrNoCredentials = errors.New("no credentials found")
+
+// Credentials are the keys or the token requests are signed with
+type Credentials struct {
+	AccessKey string
+	SecretKey string
+	Token     string
+	Expires   time.Time // zero when the credentials never expire
+}
+
+func (c *Credentials) empty() bool {
+	return c == nil || (c.Token == "" && (c.AccessKey == "" || c.SecretKey == ""))
+}
+
+func (c *Credentials) expiresWithin(d time.Duration) bool {
+	return !c.Expires.IsZero() && time.Now().Add(d).After(c.Expires)
+}
+
+// CredentialProvider resolves the credentials of every signed request
+type CredentialProvider interface {
+	Retrieve(ctx context.Context) (*Credentials, error)
+}
+
+// CredentialProviderFunc adapts a function to CredentialProvider
+type CredentialProviderFunc func(ctx context.Context) (*Credentials, error)
+
+func (f CredentialProviderFunc) Retrieve(ctx context.Context) (*Credentials, error) {
+	return f(ctx)
+}
+
+// StaticCredentials always provides cred
+func StaticCredentials(cred *Credentials) CredentialProvider {
+	return CredentialProviderFunc(func(context.Context) (*Credentials, error) {
+		if cred.empty() {
+			return nil, ErrNoCredentials
+		}
+		return cred, nil
+	})
+}
+
+// EnvCredentials reads the credentials from CTYUN_ACCESS_KEY, CTYUN_SECRET_KEY and CTYUN_TOKEN
+func EnvCredentials() CredentialProvider {
+	return CredentialProviderFunc(func(context.Context) (*Credentials, error) {
+		cred := &Credentials{
+			AccessKey: os.Getenv(EnvAccessKey),
+			SecretKey: os.Getenv(EnvSecretKey),
+			Token:     os.Getenv(EnvToken),
+		}
+		if cred.empty() {
+			return nil, ErrNoCredentials
+		}
+		return cred, nil
+	})
+}
+
+// ProfileCredentials reads the credentials of profile from an ini file holding
+// access_key, secret_key and token per profile section. The file defaults to CTYUN_CREDENTIALS_FILE
+// or ~/.ctyun/credentials, the profile to CTYUN_PROFILE or "default".
+// The file is read on every retrieval, so rotated keys are picked up without rebuilding the client.
+func ProfileCredentials(file, profile string) CredentialProvider {
+	return CredentialProviderFunc(func(context.Context) (*Credentials, error) {
+		path, name := file, profile
+		if path == "" {
+			path = os.Getenv(EnvCredentialsFile)
+		}
+		if path == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, ErrNoCredentials
+			}
+			path = filepath.Join(home, ".ctyun", "credentials")
+		}
+		if name == "" {
+			name = os.Getenv(EnvProfile)
+		}
+		if name == "" {
+			name = defaultProfile
+		}
+		values, err := readProfile(path, name)
+		if err != nil {
+			return nil, err
+		}
+		cred := &Credentials{
+			AccessKey: values["access_key"],
+			SecretKey: values["secret_key"],
+			Token:     values["token"],
+		}
+		if cred.empty() {
+			return nil, ErrNoCredentials
+		}
+		return cred, nil
+	})
+}
+
+func readProfile(path, profile string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNoCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var section string
+	values := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != profile {
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read credentials file %s failed: %v", path, err)
+	}
+	return values, nil
+}
+
+// RefreshableCredentials caches the credentials returned by refresh until shortly before they expire
+func RefreshableCredentials(refresh func(ctx context.Context) (*Credentials, error)) CredentialProvider {
+	return &refreshableCredentials{refresh: refresh}
+}
+
+type refreshableCredentials struct {
+	mu      sync.Mutex
+	refresh func(ctx context.Context) (*Credentials, error)
+	cred    *Credentials
+}
+
+func (p *refreshableCredentials) Retrieve(ctx context.Context) (*Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cred != nil && !p.cred.expiresWithin(expiryWindow) {
+		return p.cred, nil
+	}
+	cred, err := p.refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cred.empty() {
+		return nil, ErrNoCredentials
+	}
+	p.cred = cred
+	return cred, nil
+}
+
+// ChainCredentials returns the credentials of the first provider which has some
+func ChainCredentials(providers ...CredentialProvider) CredentialProvider {
+	return CredentialProviderFunc(func(ctx context.Context) (*Credentials, error) {
+		for _, p := range providers {
+			cred, err := p.Retrieve(ctx)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			return cred, err
+		}
+		return nil, ErrNoCredentials
+	})
+}
+
+// DefaultCredentials looks up the credentials in the environment variables, then in the credentials file
+func DefaultCredentials() CredentialProvider {
+	return ChainCredentials(EnvCredentials(), ProfileCredentials("", ""))
+}
+
+// defaultSigner signs requests with DefaultCredentials, and leaves them unsigned when there are none
+func defaultSigner() Signer {
+	openApi := OpenApiSigner(DefaultCredentials())
+	return SignerFunc(func(ctx context.Context, req *SigningRequest) error {
+		if err := openApi.Sign(ctx, req); !errors.Is(err, ErrNoCredentials) {
+			return err
+		}
+		return nil
+	})
+}
+
+// SigningRequest is the request handed to a Signer, the signature goes into Header
+type SigningRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Query  url.Values
+	Body   []byte // set for POST and PUT requests
+}
+
+// Signer signs every request of a client
+type Signer interface {
+	Sign(ctx context.Context, req *SigningRequest) error
+}
+
+// SignerFunc adapts a function to Signer
+type SignerFunc func(ctx context.Context, req *SigningRequest) error
+
+func (f SignerFunc) Sign(ctx context.Context, req *SigningRequest) error {
+	return f(ctx, req)
+}
+
+// OpenApiSigner signs requests with the access key and secret key of provider
+func OpenApiSigner(provider CredentialProvider) Signer {
+	return SignerFunc(func(ctx context.Context, req *SigningRequest) error {
+		cred, err := provider.Retrieve(ctx)
+		if err != nil {
+			return err
+		}
+		if cred.AccessKey == "" || cred.SecretKey == "" {
+			return fmt.Errorf("sign request failed: %w", ErrNoCredentials)
+		}
+		{{- if .Stdlib}}
+		return signEop(cred.AccessKey, cred.SecretKey, time.Now(), req)
+		{{- else}}
+		// the request id sent by the client is signed, so that the logs of both sides tell the same id
+		requestId := req.Header.Get(HeaderRequestId)
+		if requestId == "" {
+			requestId = utils.GetRandomString(32)
+		}
+		header, err := signer.NewOpenApiSigner(cred.AccessKey, cred.SecretKey).
+			SetRequestId(requestId).
+			SetHeader(req.Header).
+			SetParam(req.Query).
+			SetBody(req.Body).
+			Sign()
+		if err != nil {
+			return err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		return nil
+		{{- end}}
+	})
+}
+{{if .Stdlib}}
+// signEop signs req by the EOP algorithm of the openapi gateway
+func signEop(accessKey, secretKey string, now time.Time, req *SigningRequest) error {
+	requestId := req.Header.Get(HeaderRequestId)
+	if requestId == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return err
+		}
+		requestId = hex.EncodeToString(id)
+	}
+	date := now.Format("20060102T150405Z")
+	bodyHash := sha256.Sum256(req.Body)
+	stringToSign := "ctyun-eop-request-id:" + requestId + "\n" +
+		"eop-date:" + date + "\n" +
+		"\n" + req.Query.Encode() + "\n" + hex.EncodeToString(bodyHash[:])
+
+	kTime := hmacSHA256([]byte(secretKey), date)
+	kAk := hmacSHA256(kTime, accessKey)
+	kDate := hmacSHA256(kAk, date[:8])
+	signature := base64.StdEncoding.EncodeToString(hmacSHA256(kDate, stringToSign))
+
+	req.Header.Set("ctyun-eop-request-id", requestId)
+	req.Header.Set("Eop-date", date)
+	req.Header.Set("Eop-Authorization", accessKey+" Headers=ctyun-eop-request-id;eop-date Signature="+signature)
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+{{end}}
+// BearerTokenSigner sets the token of provider as the bearer Authorization header
+func BearerTokenSigner(provider CredentialProvider) Signer {
+	return SignerFunc(func(ctx context.Context, req *SigningRequest) error {
+		cred, err := provider.Retrieve(ctx)
+		if err != nil {
+			return err
+		}
+		if cred.Token == "" {
+			return fmt.Errorf("sign request failed: %w", ErrNoCredentials)
+		}
+		req.Header.Set("Authorization", "Bearer "+cred.Token)
+		return nil
+	})
+}
+
+// NoAuthSigner leaves requests unsigned
+func NoAuthSigner() Signer {
+	return SignerFunc(func(context.Context, *SigningRequest) error {
+		return nil
+	})
+}
+`
@@ -0,0 +1,110 @@
+package sdk
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var changes []string
+	b := newCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      20 * time.Millisecond,
+		HalfOpenRequests: 2,
+		OnStateChange: func(host string, from, to CircuitState) {
+			changes = append(changes, from.String()+">"+to.String())
+		},
+	})
+	allow := func(want bool) bool {
+		t.Helper()
+		trial, err := b.allow("h")
+		if (err == nil) != want {
+			t.Fatalf("allow returned %v, want allowed %v", err, want)
+		}
+		if err != nil && !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("allow returned %v", err)
+		}
+		return trial
+	}
+	state := func(want CircuitState) {
+		t.Helper()
+		if got := b.hosts["h"].state; got != want {
+			t.Fatalf("state %v, want %v", got, want)
+		}
+	}
+
+	// a success resets the consecutive failures
+	b.done("h", allow(true), true, true)
+	b.done("h", allow(true), false, true)
+	b.done("h", allow(true), true, true)
+	state(CircuitClosed)
+	// a request let through while closed is still running when the circuit opens
+	slow := allow(true)
+	b.done("h", allow(true), true, true)
+	state(CircuitOpen)
+	allow(false)
+
+	time.Sleep(30 * time.Millisecond)
+	first, second := allow(true), allow(true)
+	if slow || !first || !second {
+		t.Fatalf("trials %v %v, closed request %v", first, second, slow)
+	}
+	state(CircuitHalfOpen)
+	allow(false)
+	// the slow request is not one of the trials, its success does not close the circuit
+	b.done("h", slow, false, true)
+	b.done("h", first, false, true)
+	state(CircuitHalfOpen)
+	// a trial given up by its caller frees its slot
+	b.done("h", second, false, false)
+	b.done("h", allow(true), false, true)
+	state(CircuitClosed)
+
+	// a failed trial opens the circuit again
+	b.done("h", allow(true), true, true)
+	b.done("h", allow(true), true, true)
+	time.Sleep(30 * time.Millisecond)
+	b.done("h", allow(true), true, true)
+	state(CircuitOpen)
+
+	want := "closed>open|open>half-open|half-open>closed|closed>open|open>half-open|half-open>open"
+	if got := strings.Join(changes, "|"); got != want {
+		t.Fatalf("changes %s, want %s", got, want)
+	}
+}
+
+func TestCircuitRejectsBeforeBody(t *testing.T) {
+	sent := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.WriteHeader(http.StatusInternalServerError)
+	}, WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour}))
+	c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items")
+
+	// the body of a rejected request is never streamed
+	_, err := c.R().
+		SetUploadFiles(map[string]*UploadFile{"f": {FileName: "f", Reader: endlessReader{}}}).
+		SetResult(&OpenapiResponse{}).
+		Execute(http.MethodPost, "/upload")
+	if !errors.Is(err, ErrCircuitOpen) || sent != 1 {
+		t.Fatalf("error %v, sent %d", err, sent)
+	}
+	waitNoGoroutine(t, "streamMultipart")
+}
+
+func TestCircuitNotSent(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		replyJSON(w, "{}")
+	}, WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1}))
+	// an invalid method fails the request before it is sent, which is not a failure of the host
+	_, err := c.R().SetResult(&OpenapiResponse{}).Execute("BAD METHOD", "/items")
+	if err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error %v", err)
+	}
+	if _, err = c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items"); err != nil {
+		t.Fatal(err)
+	}
+}
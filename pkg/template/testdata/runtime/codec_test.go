@@ -0,0 +1,96 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type xmlItem struct {
+	XMLName xml.Name `xml:"item"`
+	Name    string   `xml:"name"`
+}
+
+// formItem is laid out as a generated message, whose first three fields are internal
+type formItem struct {
+	state         int
+	sizeCache     int
+	unknownFields int
+	Name          string `form:"name"`
+	Size          int64  `form:"size"`
+}
+
+type formResult struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// codecClient checks that requests send want as contentType and answers them with reply as replyType
+func codecClient(t *testing.T, contentType string, want []byte, replyType string, reply []byte) *HttpClient {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got := r.Header.Get("Content-Type"); got != contentType {
+			t.Errorf("sent the content type %q, want %q", got, contentType)
+		}
+		if !bytes.Equal(body, want) {
+			t.Errorf("sent %q, want %q", body, want)
+		}
+		w.Header().Set("Content-Type", replyType)
+		w.Write(reply)
+	})
+}
+
+func TestCodecXml(t *testing.T) {
+	const contentType = "application/xml; charset=utf-8"
+	c := codecClient(t, contentType, []byte(`<item><name>n</name></item>`),
+		"application/xml", []byte(`<item><name>r</name></item>`))
+	got := &xmlItem{}
+	_, err := c.R().SetContentType(contentType).SetBodyParam(&xmlItem{Name: "n"}).
+		SetResult(&OpenapiResponse{ReturnObj: got}).Execute(http.MethodPost, "/items")
+	if err != nil || got.Name != "r" {
+		t.Fatalf("decoded %+v, %v", got, err)
+	}
+}
+
+func TestCodecForm(t *testing.T) {
+	const contentType = "application/x-www-form-urlencoded"
+	c := codecClient(t, contentType, []byte(`name=n&size=2`),
+		contentType, []byte(`name=r&tags=a&tags=b`))
+	got := &formResult{}
+	_, err := c.R().SetContentType(contentType).SetBodyParam(&formItem{Name: "n", Size: 2}).
+		SetResult(&OpenapiResponse{ReturnObj: got}).Execute(http.MethodPost, "/items")
+	if err != nil || got.Name != "r" || len(got.Tags) != 2 || got.Tags[1] != "b" {
+		t.Fatalf("decoded %+v, %v", got, err)
+	}
+}
+
+func TestCodecProtobuf(t *testing.T) {
+	const contentType = "application/x-protobuf"
+	want, _ := proto.Marshal(wrapperspb.String("n"))
+	reply, _ := proto.Marshal(wrapperspb.String("r"))
+	c := codecClient(t, contentType, want, contentType, reply)
+	got := &wrapperspb.StringValue{}
+	_, err := c.R().SetContentType(contentType).SetBodyParam(wrapperspb.String("n")).
+		SetResult(&OpenapiResponse{ReturnObj: got}).Execute(http.MethodPost, "/items")
+	if err != nil || got.GetValue() != "r" {
+		t.Fatalf("decoded %v, %v", got, err)
+	}
+}
+
+func TestCodecText(t *testing.T) {
+	// a string body is sent as text without a content type
+	const contentType = "text/plain; charset=utf-8"
+	c := codecClient(t, contentType, []byte(`n`), "text/plain", []byte(`r`))
+	for _, req := range []*request{c.R(), c.R().SetContentType(contentType)} {
+		var got string
+		_, err := req.SetBodyParam("n").SetResult(&OpenapiResponse{ReturnObj: &got}).Execute(http.MethodPost, "/items")
+		if err != nil || got != "r" {
+			t.Fatalf("decoded %q, %v", got, err)
+		}
+	}
+}
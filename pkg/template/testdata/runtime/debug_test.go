@@ -0,0 +1,62 @@
+package sdk
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDebugDump(t *testing.T) {
+	var buf bytes.Buffer
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=server-secret")
+		replyJSON(w, `{"id":"1"}`)
+	}, WithDebug(&buf), WithClientConfig(&OpenapiConfig{AccessKey: "ak", SecretKey: "sk"}))
+
+	_, err := c.R().
+		SetHeader("Authorization", "Bearer secret-token").
+		SetHeader("X-Trace", "t1").
+		SetCookies(map[string]string{"session": "client-secret"}).
+		SetBodyParam(map[string]string{"name": "it's"}).
+		SetResult(&OpenapiResponse{}).
+		Execute(http.MethodPost, "/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := buf.String()
+	for _, secret := range []string{"secret-token", "client-secret", "server-secret", "sk"} {
+		if strings.Contains(dump, secret) {
+			t.Fatalf("%s is not redacted:\n%s", secret, dump)
+		}
+	}
+	url := c.hostUrl + "/items"
+	for _, want := range []string{
+		"> POST " + url + "\n",
+		"> Authorization: [REDACTED]\n",
+		"> Cookie: [REDACTED]\n",
+		"> Eop-Authorization: [REDACTED]\n",
+		"> X-Trace: t1\n",
+		`{"name":"it's"}` + "\n",
+		"< 200 OK (",
+		"< Set-Cookie: [REDACTED]\n",
+		`{"statusCode":800,"returnObj":{"id":"1"}}` + "\n",
+		"curl -X POST '" + url + "' -H 'Authorization: [REDACTED]'",
+		` -H 'X-Trace: t1' --data-binary '{"name":"it'\''s"}'` + "\n\n",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Fatalf("dump lacks %q:\n%s", want, dump)
+		}
+	}
+
+	buf.Reset()
+	c.R().
+		SetFormParams(map[string]string{"name": "n"}).
+		SetUploadFiles(map[string]*UploadFile{"image": {FileName: "a.png", Reader: strings.NewReader("IMG")}}).
+		SetResult(&OpenapiResponse{}).
+		Execute(http.MethodPost, "/upload")
+	dump = buf.String()
+	if !strings.Contains(dump, " -F 'name=n' -F 'image=@a.png'") || strings.Contains(dump, "IMG") || strings.Contains(dump, "-H 'Content-Type") {
+		t.Fatalf("multipart dump:\n%s", dump)
+	}
+}
@@ -0,0 +1,103 @@
+package sdk
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+type envelopeItem struct {
+	Name string `json:"name"`
+}
+
+type envelopeDetail struct {
+	Limit int `json:"limit"`
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	wrapped := &Envelope{CodeField: "code", SuccessCode: "0", DataPath: "data.item", ReasonField: "reason", MessageField: "msg"}
+	for _, tt := range []struct {
+		name     string
+		envelope *Envelope
+		status   int
+		body     string
+		item     string
+		err      *Status
+		detail   int
+	}{
+		{name: "data path", envelope: wrapped, body: `{"code":0,"msg":"ok","data":{"item":{"name":"a"}}}`, item: "a"},
+		{name: "string code", envelope: wrapped, body: `{"code":"0","data":{"item":{"name":"b"}}}`, item: "b"},
+		{name: "null data", envelope: wrapped, body: `{"code":0,"data":{"item":null}}`},
+		{
+			name:     "failure code",
+			envelope: wrapped,
+			body:     `{"code":40001,"reason":"OverQuota","msg":"over quota","limit":5}`,
+			err:      &Status{Code: 40001, Reason: "OverQuota", Message: "over quota"},
+			detail:   5,
+		},
+		{
+			name:     "reason falls back to the code",
+			envelope: wrapped,
+			body:     `{"code":"E1","msg":"failed"}`,
+			err:      &Status{Code: http.StatusOK, Reason: "E1", Message: "failed"},
+		},
+		{
+			name:     "failure status",
+			envelope: wrapped,
+			status:   http.StatusNotFound,
+			body:     `{"msg":"missing"}`,
+			err:      &Status{Code: http.StatusNotFound, Reason: "Not Found", Message: "missing"},
+		},
+		{
+			name:     "failure status without json",
+			envelope: wrapped,
+			status:   http.StatusBadGateway,
+			body:     `<html>bad gateway</html>`,
+			err:      &Status{Code: http.StatusBadGateway, Reason: "Bad Gateway", Message: "<html>bad gateway</html>"},
+		},
+		{name: "whole body", envelope: &Envelope{CodeField: "code", SuccessCode: "0"}, body: `{"code":0,"name":"c"}`, item: "c"},
+		{name: "none", envelope: &Envelope{None: true}, body: `{"name":"d"}`, item: "d"},
+		{
+			name:     "none failure",
+			envelope: &Envelope{None: true},
+			status:   http.StatusConflict,
+			body:     `{"limit":7}`,
+			err:      &Status{Code: http.StatusConflict, Reason: "Conflict", Message: `{"limit":7}`},
+			detail:   7,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write([]byte(tt.body))
+			})
+			item, detail := &envelopeItem{}, &envelopeDetail{}
+			r := c.R().SetEnvelope(tt.envelope).SetError(detail)
+			_, err := r.SetResult(&OpenapiResponse{ReturnObj: item}).Execute(http.MethodGet, "/items")
+			if tt.err == nil {
+				if err != nil || item.Name != tt.item {
+					t.Fatalf("item %+v, error %v", item, err)
+				}
+				return
+			}
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("error %v", err)
+			}
+			got := statusErr.ErrStatus
+			got.RequestId = ""
+			if got != *tt.err {
+				t.Fatalf("status %+v, want %+v", got, *tt.err)
+			}
+			if tt.detail != 0 && (r.Error == nil || detail.Limit != tt.detail) {
+				t.Fatalf("detail %+v", r.Error)
+			}
+			if tt.detail == 0 && r.Error != nil {
+				t.Fatalf("detail %+v is kept", r.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package sdk
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+type quotaDetail struct {
+	Limit int `json:"limit"`
+	Used  int `json:"used"`
+}
+
+func TestErrorDetail(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		status int
+		body   string
+		detail *quotaDetail
+	}{
+		{
+			name:   "return object",
+			status: http.StatusForbidden,
+			body:   `{"statusCode":900,"errorCode":"Quota.Exceeded","message":"quota","returnObj":{"limit":10,"used":11}}`,
+			detail: &quotaDetail{Limit: 10, Used: 11},
+		},
+		{
+			// the fields beside returnObj are not the detail
+			name:   "no return object",
+			status: http.StatusForbidden,
+			body:   `{"statusCode":900,"errorCode":"Quota.Exceeded","limit":10}`,
+		},
+		{name: "empty return object", status: http.StatusOK, body: `{"statusCode":900,"errorCode":"Quota.Exceeded","returnObj":{}}`},
+		{name: "null return object", status: http.StatusOK, body: `{"statusCode":900,"error":"Quota.Exceeded","returnObj":null}`},
+		{name: "zero return object", status: http.StatusOK, body: `{"statusCode":900,"error":"Quota.Exceeded","returnObj":{"limit":0}}`},
+		{name: "bad request", status: http.StatusBadRequest, body: `{"message":"bad"}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			detail := &quotaDetail{}
+			r := c.R().SetError(detail)
+			_, err := r.SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items")
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("error %v", err)
+			}
+			if tt.detail == nil {
+				if r.Error != nil {
+					t.Fatalf("detail %+v is kept", r.Error)
+				}
+				return
+			}
+			if r.Error == nil || *detail != *tt.detail {
+				t.Fatalf("detail %+v, want %+v", r.Error, tt.detail)
+			}
+		})
+	}
+}
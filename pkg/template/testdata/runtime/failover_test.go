@@ -0,0 +1,99 @@
+package sdk
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// deadEndpoint returns the url of a port nothing listens on
+func deadEndpoint(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+	return "http://" + l.Addr().String()
+}
+
+func TestFailover(t *testing.T) {
+	sent := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		replyJSON(w, "{}")
+	}))
+	defer srv.Close()
+	dead := deadEndpoint(t)
+
+	endpoints := WithEndpoints(dead, srv.URL)
+	newClient := func(opt Option) *HttpClient {
+		c, err := NewHttpClient(GetOptions(opt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	c, shared, other := newClient(endpoints), newClient(endpoints), newClient(WithEndpoints(dead, srv.URL))
+	if _, err := c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items"); err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 {
+		t.Fatalf("sent %d requests", sent)
+	}
+
+	// the clients of the same option avoid the dead endpoint, the others do not know it
+	req := c.R()
+	for _, tt := range []struct {
+		name  string
+		c     *HttpClient
+		first string
+	}{
+		{"client", c, srv.URL},
+		{"shared", shared, srv.URL},
+		{"other", other, dead},
+	} {
+		if got := tt.c.endpointsOf(req); got[0] != tt.first {
+			t.Fatalf("%s tries %v", tt.name, got)
+		}
+	}
+
+	// the dead endpoint is tried first again once the cooldown is over
+	c.endpointHealth.mu.Lock()
+	c.endpointHealth.unreachable[dead] = time.Now().Add(-time.Second)
+	c.endpointHealth.mu.Unlock()
+	if got := shared.endpointsOf(req); got[0] != dead {
+		t.Fatalf("the recovered endpoint is not preferred: %v", got)
+	}
+
+	// a request reaching an endpoint prefers it again at once
+	c.endpointHealth.failed(srv.URL)
+	if _, err := c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.endpointsOf(req); got[0] != srv.URL {
+		t.Fatalf("the reached endpoint is not preferred: %v", got)
+	}
+}
+
+func TestFailoverNotRetried(t *testing.T) {
+	var hosts []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		hosts = append(hosts, r.Host)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	first, second := httptest.NewServer(http.HandlerFunc(handler)), httptest.NewServer(http.HandlerFunc(handler))
+	defer first.Close()
+	defer second.Close()
+	c, err := NewHttpClient(GetOptions(WithEndpoints(first.URL, second.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a server which answers is not failed over, whatever it answers
+	c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items")
+	if len(hosts) != 1 || "http://"+hosts[0] != first.URL {
+		t.Fatalf("sent to %v", hosts)
+	}
+}
@@ -0,0 +1,25 @@
+package sdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient starts a server answering with handler and returns a client of it
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *HttpClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c, err := NewHttpClient(GetOptions(append([]Option{WithHostUrl(srv.URL)}, opts...)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// replyJSON answers with the openapi response returning obj
+func replyJSON(w http.ResponseWriter, obj string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"statusCode":800,"returnObj":` + obj + `}`))
+}
@@ -0,0 +1,48 @@
+package sdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetHostUrl(t *testing.T) {
+	var got string
+	server := func(name string) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = name
+			replyJSON(w, `{}`)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	client, method := server("client"), server("method")
+
+	// the host of a generated client is its base domain, which the base url of a method replaces
+	opts := GetOptions()
+	opts.hostUrl = client.URL
+	c, err := NewHttpClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// WithHostUrl sends every request to its host
+	override, err := NewHttpClient(GetOptions(WithHostUrl(client.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		c       *HttpClient
+		hostUrl string
+		want    string
+	}{
+		{c, "", "client"},
+		{c, method.URL, "method"},
+		{override, method.URL, "client"},
+	} {
+		got = ""
+		_, err = tc.c.R().SetHostUrl(tc.hostUrl).SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items")
+		if err != nil || got != tc.want {
+			t.Fatalf("sent to %q, want %q: %v", got, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestInterceptors(t *testing.T) {
+	var order []string
+	requestInterceptor := func(name string) RequestInterceptor {
+		return func(ctx context.Context, info *CallInfo, req interface{}, header http.Header) error {
+			order = append(order, name+" "+info.Service+"."+info.Method+" "+info.Verb)
+			header.Add("X-Seen", name)
+			if req == "abort" {
+				return errors.New("aborted by " + name)
+			}
+			return nil
+		}
+	}
+	responseInterceptor := func(name string) ResponseInterceptor {
+		return func(ctx context.Context, info *CallInfo, req, resp interface{}, err error) error {
+			order = append(order, name)
+			if err != nil {
+				return errors.New(name + ": " + err.Error())
+			}
+			return nil
+		}
+	}
+	sent := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		order = append(order, "server "+strings.Join(r.Header.Values("X-Seen"), ","))
+		replyJSON(w, "{}")
+	},
+		WithRequestInterceptor(requestInterceptor("req1"), requestInterceptor("req2")),
+		WithRequestInterceptor(requestInterceptor("req3")),
+		WithResponseInterceptor(responseInterceptor("resp1"), responseInterceptor("resp2")),
+	)
+
+	call := func(req interface{}) error {
+		r := c.R().SetCallInfo(&CallInfo{Service: "Item", Method: "Get"}, req)
+		_, err := r.SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items")
+		return r.interceptResponse(nil, err)
+	}
+	if err := call("ok"); err != nil {
+		t.Fatal(err)
+	}
+	want := "req1 Item.Get GET|req2 Item.Get GET|req3 Item.Get GET|server req1,req2,req3|resp1|resp2"
+	if got := strings.Join(order, "|"); got != want {
+		t.Fatalf("order %s, want %s", got, want)
+	}
+
+	order = nil
+	err := call("abort")
+	if err == nil || err.Error() != "resp2: resp1: aborted by req1" || sent != 1 {
+		t.Fatalf("abort %v, sent %d", err, sent)
+	}
+	if got := strings.Join(order, "|"); got != "req1 Item.Get GET|resp1|resp2" {
+		t.Fatalf("order %s", got)
+	}
+}
@@ -0,0 +1,168 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// polls answers the polls with the statuses and errors in order
+func polls(results ...interface{}) (func(ctx context.Context) (string, error), *int) {
+	n := new(int)
+	return func(ctx context.Context) (string, error) {
+		result := results[*n]
+		*n++
+		if err, ok := result.(error); ok {
+			return "", err
+		}
+		return result.(string), nil
+	}, n
+}
+
+func TestWaitOperation(t *testing.T) {
+	unavailable := &StatusError{ErrStatus: Status{Code: http.StatusServiceUnavailable}}
+	forbidden := &StatusError{ErrStatus: Status{Code: http.StatusForbidden}}
+	opts := func() *WaitOptions {
+		return &WaitOptions{Interval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+	}
+	for _, tt := range []struct {
+		name    string
+		results []interface{}
+		polled  int
+		err     func(err error) bool
+	}{
+		{name: "success", results: []interface{}{"running", "running", "done"}, polled: 3},
+		{
+			name:    "failure",
+			results: []interface{}{"running", "failed"},
+			polled:  2,
+			err: func(err error) bool {
+				var opErr *OperationError
+				return errors.As(err, &opErr) && opErr.JobId == "job-1" && opErr.Status == "failed"
+			},
+		},
+		{name: "retried", results: []interface{}{"running", unavailable, unavailable, "done"}, polled: 4},
+		{
+			name:    "retries reset",
+			results: []interface{}{unavailable, unavailable, unavailable, "running", unavailable, unavailable, unavailable, "done"},
+			polled:  8,
+		},
+		{
+			name:    "retries exhausted",
+			results: []interface{}{unavailable, unavailable, unavailable, unavailable},
+			polled:  4,
+			err:     func(err error) bool { return err == unavailable },
+		},
+		{
+			name:    "not retryable",
+			results: []interface{}{"running", forbidden},
+			polled:  2,
+			err:     func(err error) bool { return err == forbidden },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			poll, polled := polls(tt.results...)
+			err := waitOperation(context.Background(), opts(), "job-1", poll, []string{"done"}, []string{"failed"})
+			if tt.err == nil && err != nil || tt.err != nil && !tt.err(err) {
+				t.Fatalf("error %v", err)
+			}
+			if *polled != tt.polled {
+				t.Fatalf("polled %d times, want %d", *polled, tt.polled)
+			}
+		})
+	}
+}
+
+func TestWaitOperationProgress(t *testing.T) {
+	var got []string
+	opts := &WaitOptions{Interval: time.Millisecond, Progress: func(status string, polls int) {
+		got = append(got, status+" "+string(rune('0'+polls)))
+	}}
+	poll, _ := polls("pending", &StatusError{ErrStatus: Status{Code: http.StatusTooManyRequests}}, "running", "done")
+	if err := waitOperation(context.Background(), opts, "job-1", poll, []string{"done"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	// failed polls are not reported
+	if want := []string{"pending 1", "running 3", "done 4"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("progress %v, want %v", got, want)
+	}
+}
+
+func TestWaitOperationBackoff(t *testing.T) {
+	var times []time.Time
+	poll := func(ctx context.Context) (string, error) {
+		times = append(times, time.Now())
+		if len(times) == 5 {
+			return "done", nil
+		}
+		return "running", nil
+	}
+	opts := &WaitOptions{Interval: 20 * time.Millisecond, MaxInterval: 50 * time.Millisecond, Backoff: 2}
+	if err := waitOperation(context.Background(), opts, "job-1", poll, []string{"done"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	// 20ms, 40ms, then 50ms capped by MaxInterval
+	for i, min := range []time.Duration{20, 40, 50, 50} {
+		if gap := times[i+1].Sub(times[i]); gap < min*time.Millisecond {
+			t.Fatalf("poll %d came %v after the previous one, want at least %v", i+2, gap, min*time.Millisecond)
+		}
+	}
+}
+
+func TestWaitOperationTimeout(t *testing.T) {
+	poll := func(ctx context.Context) (string, error) { return "running", nil }
+	opts := &WaitOptions{Interval: time.Millisecond, Timeout: 20 * time.Millisecond}
+	err := waitOperation(context.Background(), opts, "job-1", poll, []string{"done"}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	poll = func(ctx context.Context) (string, error) {
+		cancel()
+		return "", ctx.Err()
+	}
+	err = waitOperation(ctx, &WaitOptions{Interval: time.Millisecond}, "job-1", poll, []string{"done"}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error %v", err)
+	}
+}
+
+func TestRetryablePoll(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{&StatusError{ErrStatus: Status{Code: http.StatusBadGateway}}, true},
+		{&StatusError{ErrStatus: Status{Code: http.StatusTooManyRequests}}, true},
+		{&StatusError{ErrStatus: Status{Code: http.StatusNotFound}}, false},
+		{&StatusError{ErrStatus: Status{Code: 900}}, false},
+		{errors.New("bad json"), false},
+	} {
+		if got := retryablePoll(tt.err); got != tt.want {
+			t.Fatalf("retryablePoll(%v) = %v", tt.err, got)
+		}
+	}
+	// a poll not reaching the server is retried
+	c, err := NewHttpClient(GetOptions(WithHostUrl(deadEndpoint(t))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/jobs/1")
+	if !retryablePoll(err) {
+		t.Fatalf("%v is not retried", err)
+	}
+	// and so is one answered by the error page of a gateway
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("<html>unavailable</html>"))
+	})
+	_, err = c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/jobs/1")
+	if !retryablePoll(err) {
+		t.Fatalf("%v is not retried", err)
+	}
+}
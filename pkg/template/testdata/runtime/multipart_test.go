@@ -0,0 +1,82 @@
+package sdk
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultipartUpload(t *testing.T) {
+	var transferred int64
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+			return
+		}
+		f, header, err := r.FormFile("image")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		data, _ := io.ReadAll(f)
+		if r.FormValue("name") != "n" || string(data) != "IMG" || header.Filename != `a"b.png` || header.Header.Get("Content-Type") != "image/png" {
+			t.Errorf("form %v, file %s %q %v", r.MultipartForm.Value, data, header.Filename, header.Header)
+		}
+		replyJSON(w, "{}")
+	})
+	_, err := c.R().
+		SetFormParams(map[string]string{"name": "n"}).
+		SetUploadFiles(map[string]*UploadFile{"image": {
+			FileName:    `a"b.png`,
+			ContentType: "image/png",
+			Reader:      strings.NewReader("IMG"),
+			Size:        3,
+			Progress:    func(n, total int64) { transferred = n },
+		}}).
+		SetResult(&OpenapiResponse{}).
+		Execute(http.MethodPost, "/upload")
+	if err != nil || transferred != 3 {
+		t.Fatalf("upload %v, transferred %d", err, transferred)
+	}
+}
+
+// endlessReader never ends, so the multipart goroutine only stops when the body is closed
+type endlessReader struct{}
+
+func (endlessReader) Read(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func TestMultipartClosedOnFailure(t *testing.T) {
+	failing := WithClient(DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("refused")
+	}))
+	c := newTestClient(t, nil, failing)
+	_, err := c.R().
+		SetUploadFiles(map[string]*UploadFile{"f": {FileName: "f", Reader: endlessReader{}}}).
+		SetResult(&OpenapiResponse{}).
+		Execute(http.MethodPost, "/upload")
+	if err == nil {
+		t.Fatal("expected the doer error")
+	}
+	waitNoGoroutine(t, "streamMultipart")
+}
+
+// waitNoGoroutine fails when a goroutine running fn is still alive after a second
+func waitNoGoroutine(t *testing.T, fn string) {
+	t.Helper()
+	buf := make([]byte, 1<<20)
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		stacks := string(buf[:runtime.Stack(buf, true)])
+		if !strings.Contains(stacks, fn) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s goroutine leaked:\n%s", fn, stacks)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package sdk
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+type queryFilter struct {
+	Name   string   `json:"name,omitempty"`
+	Values []string `json:"values,omitempty"`
+}
+
+func TestQueryEncoding(t *testing.T) {
+	var query url.Values
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		replyJSON(w, "{}")
+	})
+	typ := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	for _, tc := range []struct {
+		style string
+		value interface{}
+		want  url.Values
+	}{
+		{"", []string{"a", "b"}, url.Values{"p": {"a", "b"}}},
+		{"form_no_explode", []int32{1, 2}, url.Values{"p": {"1,2"}}},
+		{"", &queryFilter{Name: "n", Values: []string{"x", "y"}}, url.Values{"p.name": {"n"}, "p.values": {"x", "y"}}},
+		{"deep_object", &queryFilter{Name: "n"}, url.Values{"p[name]": {"n"}}},
+		{"", map[string]string{"b": "2", "a": "1"}, url.Values{"p.a": {"1"}, "p.b": {"2"}}},
+		{"json", &queryFilter{Name: "n"}, url.Values{"p": {`{"name":"n"}`}}},
+		{"", typ, url.Values{"p": {"TYPE_STRING"}}},
+		{"", []*queryFilter{{Name: "a"}, {Name: "b"}}, url.Values{"p.0.name": {"a"}, "p.1.name": {"b"}}},
+		{"", (*queryFilter)(nil), url.Values{}},
+	} {
+		_, err := c.R().
+			SetQueryStyles(map[string]string{"p": tc.style}).
+			SetQueryParam("p", tc.value).
+			SetResult(&OpenapiResponse{}).
+			Execute(http.MethodGet, "/query")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query.Encode() != tc.want.Encode() {
+			t.Errorf("style %q of %#v: query %s, want %s", tc.style, tc.value, query.Encode(), tc.want.Encode())
+		}
+	}
+
+	// enums are sent as their numbers with queryEnumAsInt
+	r := c.R()
+	r.queryEnumAsInt = true
+	if _, err := r.SetQueryParam("p", typ).SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/query"); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("p") != "9" {
+		t.Errorf("enum as int %s", query.Get("p"))
+	}
+}
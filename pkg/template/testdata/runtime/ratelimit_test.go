@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(50, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := bucket.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the burst is taken at once, the other two tokens are refilled every 20ms
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond || elapsed > time.Second {
+		t.Fatalf("4 tokens took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	slow := NewTokenBucket(1, 1)
+	slow.Wait(context.Background())
+	if err := slow.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("canceled wait %v", err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var sent int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sent, 1)
+		replyJSON(w, "{}")
+	}
+	call := func(c *HttpClient, ctx context.Context, method string) error {
+		_, err := c.R().SetContext(ctx).
+			SetCallInfo(&CallInfo{Service: "Item", Method: method}, nil).
+			SetResult(&OpenapiResponse{}).
+			Execute(http.MethodGet, "/items")
+		return err
+	}
+
+	// the limit of Item.List replaces the limit of the client, which blocks the other methods
+	c := newTestClient(t, handler, WithRateLimit(0.001, 1), WithMethodRateLimit("Item.List", 1000, 10))
+	for i := 0; i < 5; i++ {
+		if err := call(c, context.Background(), "List"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := call(c, context.Background(), "Get"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := call(c, ctx, "Get"); !errors.Is(err, context.DeadlineExceeded) || atomic.LoadInt32(&sent) != 6 {
+		t.Fatalf("limited call %v, sent %d", err, sent)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	if peak := peakInFlight(t, WithMaxInFlight(2)); peak != 2 {
+		t.Fatalf("peak of %d requests in flight", peak)
+	}
+	// zero and negative values lift the limit, including the one set before them
+	for _, n := range []int{0, -1} {
+		if peak := peakInFlight(t, WithMaxInFlight(2), WithMaxInFlight(n)); peak != 8 {
+			t.Fatalf("peak of %d requests in flight with WithMaxInFlight(%d)", peak, n)
+		}
+	}
+}
+
+// peakInFlight sends 8 requests at once and returns the most the server handled at the same time
+func peakInFlight(t *testing.T, opts ...Option) int32 {
+	t.Helper()
+	var inFlight, peak int32
+	arrived := make(chan struct{}, 8)
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		arrived <- struct{}{}
+		select {
+		case <-release:
+		case <-time.After(50 * time.Millisecond):
+		}
+		atomic.AddInt32(&inFlight, -1)
+		replyJSON(w, "{}")
+	}, opts...)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	// the requests are held until all of them arrived, or for 50ms when the limit keeps them out
+	timeout := time.After(time.Second)
+	for i := 0; i < 8; i++ {
+		select {
+		case <-arrived:
+		case <-timeout:
+			t.Fatalf("%d requests arrived", i)
+		}
+	}
+	close(release)
+	wg.Wait()
+	return atomic.LoadInt32(&peak)
+}
@@ -0,0 +1,148 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	gets := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/items":
+			gets++
+			replyJSON(w, `{"name":"`+r.URL.Query().Get("name")+string(rune('0'+gets))+`"}`)
+		case r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			replyJSON(w, string(body))
+		case r.URL.Path == "/blob":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte{0xff, 0xfe, 0x00, 0x01})
+		}
+	}))
+	dir := t.TempDir()
+	signer := WithSigner(OpenApiSigner(StaticCredentials(&Credentials{AccessKey: "ak-secret", SecretKey: "sk-secret"})))
+	newClient := func(mode RecordMode) *HttpClient {
+		rec, err := NewRecorder(dir, mode, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := NewHttpClient(GetOptions(WithHostUrl(srv.URL), WithClient(rec), signer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	type item struct {
+		Name string `json:"name"`
+		A    int    `json:"a"`
+		B    int    `json:"b"`
+	}
+	get := func(c *HttpClient) (string, error) {
+		got := &item{}
+		_, err := c.R().SetQueryParam("name", "a").SetResult(&OpenapiResponse{ReturnObj: got}).Execute(http.MethodGet, "/items")
+		return got.Name, err
+	}
+	post := func(c *HttpClient, body string) (*item, error) {
+		got := &item{}
+		_, err := c.R().SetContentType("application/json").SetRawBody([]byte(body)).
+			SetResult(&OpenapiResponse{ReturnObj: got}).Execute(http.MethodPost, "/items")
+		return got, err
+	}
+	blob := func(c *HttpClient) ([]byte, error) {
+		var buf bytes.Buffer
+		_, err := c.R().SetOutput(&buf, nil).SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/blob")
+		return buf.Bytes(), err
+	}
+
+	c := newClient(ModeRecord)
+	for _, want := range []string{"a1", "a2"} {
+		if name, err := get(c); err != nil || name != want {
+			t.Fatalf("recorded %q, %v", name, err)
+		}
+	}
+	if _, err := post(c, `{"b":2,"a":1}`); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := blob(c); err != nil || !bytes.Equal(data, []byte{0xff, 0xfe, 0x00, 0x01}) {
+		t.Fatalf("recorded %v, %v", data, err)
+	}
+	srv.Close()
+
+	fixtures, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(fixtures) != 4 {
+		t.Fatalf("fixtures %v, %v", fixtures, err)
+	}
+	for _, name := range fixtures {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "ak-secret") || strings.Contains(string(data), "Signature=") {
+			t.Fatalf("the credentials are recorded in %s:\n%s", name, data)
+		}
+		if !json.Valid(data) {
+			t.Fatalf("fixture %s is not JSON", name)
+		}
+	}
+
+	// the server is gone, the exchanges are replayed in the order they were recorded
+	c = newClient(ModeReplay)
+	for _, want := range []string{"a1", "a2", "a2"} {
+		if name, err := get(c); err != nil || name != want {
+			t.Fatalf("replayed %q, %v, want %q", name, err, want)
+		}
+	}
+	// the body matches whatever the order of its keys
+	if got, err := post(c, `{"a":1,"b":2}`); err != nil || got.A != 1 || got.B != 2 {
+		t.Fatalf("replayed %+v, %v", got, err)
+	}
+	if data, err := blob(c); err != nil || !bytes.Equal(data, []byte{0xff, 0xfe, 0x00, 0x01}) {
+		t.Fatalf("replayed %v, %v", data, err)
+	}
+	if _, err := post(c, `{"a":3}`); err == nil || !strings.Contains(err.Error(), "record it with "+EnvClientRecord+"=record") {
+		t.Fatalf("a request which was not recorded is replayed: %v", err)
+	}
+}
+
+func TestRecorderRepeatedHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// cookies are not recorded, they carry credentials as well
+		w.Header().Add("Link", "</items?page=1>; rel=first")
+		w.Header().Add("Link", "</items?page=2>; rel=next")
+		replyJSON(w, `{}`)
+	}))
+	dir := t.TempDir()
+	links := func(mode RecordMode) []string {
+		rec, err := NewRecorder(dir, mode, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := NewHttpClient(GetOptions(WithHostUrl(srv.URL), WithClient(rec)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.RawResponse.Header.Values("Link")
+	}
+
+	want := []string{"</items?page=1>; rel=first", "</items?page=2>; rel=next"}
+	if got := links(ModeRecord); !reflect.DeepEqual(got, want) {
+		t.Fatalf("recorded links %v", got)
+	}
+	srv.Close()
+	if got := links(ModeReplay); !reflect.DeepEqual(got, want) {
+		t.Fatalf("replayed links %v, want %v", got, want)
+	}
+}
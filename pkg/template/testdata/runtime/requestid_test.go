@@ -0,0 +1,97 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRequestId(t *testing.T) {
+	var got string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(HeaderRequestId)
+		replyJSON(w, `{}`)
+	}
+	traced := WithRequestIdFunc(func(ctx context.Context) string {
+		return "trace-1"
+	})
+	send := func(c *HttpClient, ctx context.Context) string {
+		t.Helper()
+		if _, err := c.R().SetContext(ctx).SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items"); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	c, tracing := newTestClient(t, handler), newTestClient(t, handler, traced)
+	incoming := WithRequestId(context.Background(), "incoming-1")
+	for _, tt := range []struct {
+		name string
+		c    *HttpClient
+		ctx  context.Context
+		want string
+	}{
+		{"context", c, incoming, "incoming-1"},
+		{"func", tracing, context.Background(), "trace-1"},
+		{"context before func", tracing, incoming, "incoming-1"},
+	} {
+		if id := send(tt.c, tt.ctx); id != tt.want {
+			t.Fatalf("%s: sent the request id %q, want %q", tt.name, id, tt.want)
+		}
+	}
+
+	// a random id is sent for every request otherwise
+	first, second := send(c, context.Background()), send(c, context.Background())
+	if len(first) != 36 || first == second {
+		t.Fatalf("sent the request ids %q and %q", first, second)
+	}
+}
+
+func TestIdempotencyKeyFailover(t *testing.T) {
+	const header = "Idempotency-Key"
+	var sent []string
+	doer := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.Header.Get(HeaderRequestId)+" "+req.Header.Get(header))
+		if req.URL.Host == "dead.example" {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"statusCode":800,"returnObj":{}}`)),
+			Request:    req,
+		}, nil
+	})
+	c, err := NewHttpClient(GetOptions(WithClient(doer), WithEndpoints("http://dead.example", "http://live.example")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := func(ctx context.Context) []string {
+		t.Helper()
+		sent = nil
+		_, err := c.R().SetContext(ctx).SetIdempotencyHeader(header).SetResult(&OpenapiResponse{}).
+			Execute(http.MethodPost, "/items")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sent
+	}
+
+	// the request keeps its ids when it fails over to the next endpoint
+	first := send(context.Background())
+	if len(first) != 2 || first[0] != first[1] || strings.HasSuffix(first[0], " ") {
+		t.Fatalf("sent %q", first)
+	}
+	// the dead endpoint is tried last now, a new call gets a new key
+	if second := send(context.Background()); len(second) != 1 || second[0] == first[0] {
+		t.Fatalf("sent %q, then %q", first, second)
+	}
+	keyed := send(WithIdempotencyKey(context.Background(), "key-1"))
+	if len(keyed) != 1 || !strings.HasSuffix(keyed[0], " key-1") {
+		t.Fatalf("sent %q", keyed)
+	}
+}
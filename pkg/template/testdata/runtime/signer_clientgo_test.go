@@ -0,0 +1,45 @@
+//go:build clientgo
+
+package sdk
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/telecom-cloud/client-go/pkg/openapi/signer"
+)
+
+// TestSignEopClientGo checks signEop signs as the signer of client-go, which dates the request itself
+func TestSignEopClientGo(t *testing.T) {
+	const requestId = "123e4567-e89b-12d3-a456-426614174000"
+	query := url.Values{"a": {"1"}, "b": {"x y"}}
+	body := []byte(`{"name":"n"}`)
+	header, err := signer.NewOpenApiSigner("ak-test", "sk-test").
+		SetRequestId(requestId).
+		SetHeader(http.Header{}).
+		SetParam(query).
+		SetBody(body).
+		Sign()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := http.Header{}
+	for k, v := range header {
+		want[http.CanonicalHeaderKey(k)] = v
+	}
+	now, err := time.Parse("20060102T150405Z", want.Get("Eop-Date"))
+	if err != nil {
+		t.Fatalf("client-go signed with the date %q: %v", want.Get("Eop-Date"), err)
+	}
+
+	req := &SigningRequest{Method: http.MethodPost, Header: http.Header{}, Query: query, Body: body}
+	req.Header.Set(HeaderRequestId, requestId)
+	if err = signEop("ak-test", "sk-test", now, req); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("Eop-Authorization"); got != want.Get("Eop-Authorization") {
+		t.Fatalf("signed %q, client-go signed %q", got, want.Get("Eop-Authorization"))
+	}
+}
@@ -0,0 +1,218 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignEop(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tt := range []struct {
+		name      string
+		query     url.Values
+		body      []byte
+		signature string
+	}{
+		{
+			name:      "query and body",
+			query:     url.Values{"a": {"1"}, "b": {"x y"}},
+			body:      []byte(`{"name":"n"}`),
+			signature: "oDbcmeGUm4f5IZqIgVfl+OzA41TrlGdP7y/yDl/Won4=",
+		},
+		{
+			name:      "empty",
+			query:     url.Values{},
+			signature: "ICb/j15Nox0wbZ9wrVE4k1UZGKabR8DTkRCc8wBBMM0=",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &SigningRequest{Method: http.MethodPost, Header: http.Header{}, Query: tt.query, Body: tt.body}
+			req.Header.Set(HeaderRequestId, "123e4567-e89b-12d3-a456-426614174000")
+			if err := signEop("ak-test", "sk-test", now, req); err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{
+				"Eop-Authorization":    "ak-test Headers=ctyun-eop-request-id;eop-date Signature=" + tt.signature,
+				"Eop-Date":             "20240102T030405Z",
+				"Ctyun-Eop-Request-Id": "123e4567-e89b-12d3-a456-426614174000",
+			}
+			for k, v := range want {
+				if got := req.Header.Get(k); got != v {
+					t.Fatalf("header %s is %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
+
+func TestSignEopRequestId(t *testing.T) {
+	req := &SigningRequest{Header: http.Header{}, Query: url.Values{}}
+	if err := signEop("ak-test", "sk-test", time.Now(), req); err != nil {
+		t.Fatal(err)
+	}
+	if id := req.Header.Get("ctyun-eop-request-id"); len(id) != 32 {
+		t.Fatalf("the generated request id is %q", id)
+	}
+}
+
+// withoutCredentials hides the credentials of the environment and the credentials file from DefaultCredentials
+func withoutCredentials(t *testing.T) {
+	for _, env := range []string{EnvAccessKey, EnvSecretKey, EnvToken, EnvProfile} {
+		t.Setenv(env, "")
+	}
+	t.Setenv(EnvCredentialsFile, filepath.Join(t.TempDir(), "credentials"))
+}
+
+func TestDefaultSigner(t *testing.T) {
+	withoutCredentials(t)
+	var authorization string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Eop-Authorization")
+		replyJSON(w, `{}`)
+	})
+	if _, err := c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items"); err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "" {
+		t.Fatalf("signed %q without credentials", authorization)
+	}
+
+	t.Setenv(EnvAccessKey, "ak-env")
+	t.Setenv(EnvSecretKey, "sk-env")
+	if _, err := c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(authorization, "ak-env ") {
+		t.Fatalf("signed %q, want the keys of the environment", authorization)
+	}
+}
+
+func TestChainCredentials(t *testing.T) {
+	var tried []string
+	provider := func(name string, cred *Credentials, err error) CredentialProvider {
+		return CredentialProviderFunc(func(context.Context) (*Credentials, error) {
+			tried = append(tried, name)
+			return cred, err
+		})
+	}
+	chain := ChainCredentials(
+		provider("none", nil, ErrNoCredentials),
+		provider("first", &Credentials{AccessKey: "ak-1", SecretKey: "sk-1"}, nil),
+		provider("second", &Credentials{AccessKey: "ak-2", SecretKey: "sk-2"}, nil),
+	)
+	cred, err := chain.Retrieve(context.Background())
+	if err != nil || cred.AccessKey != "ak-1" || strings.Join(tried, ",") != "none,first" {
+		t.Fatalf("retrieved %+v, %v after trying %v", cred, err, tried)
+	}
+
+	// a provider failing otherwise than having no credentials ends the chain
+	failed := errors.New("failed")
+	tried = nil
+	_, err = ChainCredentials(provider("failed", nil, failed), provider("first", &Credentials{Token: "t"}, nil)).
+		Retrieve(context.Background())
+	if !errors.Is(err, failed) || strings.Join(tried, ",") != "failed" {
+		t.Fatalf("retrieved %v after trying %v", err, tried)
+	}
+
+	if _, err = ChainCredentials(provider("none", nil, ErrNoCredentials)).Retrieve(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("retrieved %v", err)
+	}
+}
+
+func TestProfileCredentials(t *testing.T) {
+	withoutCredentials(t)
+	file := filepath.Join(t.TempDir(), "credentials")
+	err := os.WriteFile(file, []byte(`# keys of the accounts
+[default]
+access_key = ak-default
+secret_key = sk-default
+
+; the token of dev
+[ dev ]
+token=token-dev
+access_key = ak-ignored
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name, file, profile, envFile, envProfile string
+		want                                     Credentials
+		err                                      error
+	}{
+		{name: "default", file: file, want: Credentials{AccessKey: "ak-default", SecretKey: "sk-default"}},
+		{name: "profile", file: file, profile: "dev", want: Credentials{AccessKey: "ak-ignored", Token: "token-dev"}},
+		{name: "env", envFile: file, envProfile: "dev", want: Credentials{AccessKey: "ak-ignored", Token: "token-dev"}},
+		{name: "missing profile", file: file, profile: "prod", err: ErrNoCredentials},
+		{name: "missing file", file: file + ".missing", err: ErrNoCredentials},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.envFile != "" {
+				t.Setenv(EnvCredentialsFile, tt.envFile)
+			}
+			t.Setenv(EnvProfile, tt.envProfile)
+			cred, err := ProfileCredentials(tt.file, tt.profile).Retrieve(context.Background())
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("retrieved %+v, %v", cred, err)
+				}
+				return
+			}
+			if err != nil || *cred != tt.want {
+				t.Fatalf("retrieved %+v, %v", cred, err)
+			}
+		})
+	}
+}
+
+func TestRefreshableCredentials(t *testing.T) {
+	refreshes := 0
+	expires := time.Now().Add(time.Hour)
+	provider := RefreshableCredentials(func(context.Context) (*Credentials, error) {
+		refreshes++
+		return &Credentials{Token: "token", Expires: expires}, nil
+	})
+	retrieve := func(want int) {
+		t.Helper()
+		if _, err := provider.Retrieve(context.Background()); err != nil || refreshes != want {
+			t.Fatalf("refreshed %d times, want %d: %v", refreshes, want, err)
+		}
+	}
+	retrieve(1)
+	retrieve(1)
+
+	// credentials expiring within the window are refreshed before they are used
+	expires = time.Now().Add(expiryWindow / 2)
+	provider = RefreshableCredentials(func(context.Context) (*Credentials, error) {
+		refreshes++
+		return &Credentials{Token: "token", Expires: expires}, nil
+	})
+	refreshes = 0
+	retrieve(1)
+	retrieve(2)
+	expires = time.Now().Add(time.Hour)
+	retrieve(3)
+	retrieve(3)
+}
+
+func TestBearerTokenSigner(t *testing.T) {
+	req := &SigningRequest{Header: http.Header{}, Query: url.Values{}}
+	err := BearerTokenSigner(StaticCredentials(&Credentials{Token: "token"})).Sign(context.Background(), req)
+	if err != nil || req.Header.Get("Authorization") != "Bearer token" {
+		t.Fatalf("signed %q, %v", req.Header.Get("Authorization"), err)
+	}
+
+	req = &SigningRequest{Header: http.Header{}, Query: url.Values{}}
+	err = BearerTokenSigner(StaticCredentials(&Credentials{AccessKey: "ak", SecretKey: "sk"})).Sign(context.Background(), req)
+	if !errors.Is(err, ErrNoCredentials) || req.Header.Get("Authorization") != "" {
+		t.Fatalf("signed %q, %v without a token", req.Header.Get("Authorization"), err)
+	}
+}
@@ -0,0 +1,131 @@
+package sdk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// selfSigned returns a PEM certificate and key for name
+func selfSigned(t *testing.T, name string) (cert, key []byte) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestTLS(t *testing.T) {
+	var peers []string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, cert := range r.TLS.PeerCertificates {
+			peers = append(peers, cert.Subject.CommonName)
+		}
+		replyJSON(w, "{}")
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM := selfSigned(t, "crafter-client")
+	certFile, keyFile := filepath.Join(t.TempDir(), "cert.pem"), filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		ok   bool
+		peer bool
+	}{
+		{name: "untrusted"},
+		{name: "ca pem", opts: []Option{WithCAPEM(caPEM)}, ok: true},
+		{name: "ca file", opts: []Option{WithCAFile(caFile)}, ok: true},
+		{name: "server name", opts: []Option{WithCAPEM(caPEM), WithServerName("example.com")}, ok: true},
+		{name: "wrong server name", opts: []Option{WithCAPEM(caPEM), WithServerName("example.org")}},
+		{name: "insecure", opts: []Option{WithInsecureSkipVerify()}, ok: true},
+		{name: "client pem", opts: []Option{WithCAPEM(caPEM), WithClientCertificatePEM(certPEM, keyPEM)}, ok: true, peer: true},
+		{name: "client file", opts: []Option{WithCAFile(caFile), WithClientCertificate(certFile, keyFile)}, ok: true, peer: true},
+		{name: "replaced", opts: []Option{WithInsecureSkipVerify(), WithTLS(TLSConfig{CAPEM: caPEM})}, ok: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			peers = nil
+			c, err := NewHttpClient(GetOptions(append([]Option{WithHostUrl(srv.URL)}, tt.opts...)...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = c.R().SetResult(&OpenapiResponse{}).Execute(http.MethodGet, "/items")
+			if (err == nil) != tt.ok {
+				t.Fatalf("request error %v", err)
+			}
+			if tt.peer != (len(peers) == 1 && peers[0] == "crafter-client") {
+				t.Fatalf("the server saw the client certificates %v", peers)
+			}
+		})
+	}
+}
+
+func TestTLSConfigBuild(t *testing.T) {
+	cfg, err := TLSConfig{}.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MinVersion != tls.VersionTLS12 || cfg.RootCAs != nil || cfg.InsecureSkipVerify {
+		t.Fatalf("the default config is %+v", cfg)
+	}
+	cfg, err = TLSConfig{MinVersion: tls.VersionTLS13, ServerName: "example.com"}.build()
+	if err != nil || cfg.MinVersion != tls.VersionTLS13 || cfg.ServerName != "example.com" {
+		t.Fatalf("config %+v, error %v", cfg, err)
+	}
+
+	for name, config := range map[string]TLSConfig{
+		"no certificate in the CA": {CAPEM: []byte("not a certificate")},
+		"missing CA file":          {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"missing client key":       {CertFile: filepath.Join(t.TempDir(), "cert.pem")},
+		"bad client pem":           {CertPEM: []byte("bad"), KeyPEM: []byte("bad")},
+	} {
+		if _, err = config.build(); err == nil {
+			t.Fatalf("%s is accepted", name)
+		}
+		// the config is built when the client is created
+		if _, err = NewHttpClient(GetOptions(WithTLS(config))); err == nil {
+			t.Fatalf("a client is created with %s", name)
+		}
+	}
+}
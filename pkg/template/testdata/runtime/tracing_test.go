@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type testTracer struct {
+	names []string
+	spans []*testSpan
+}
+
+func (tr *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{}
+	tr.names = append(tr.names, name)
+	tr.spans = append(tr.spans, span)
+	return ctx, span
+}
+
+type testSpan struct {
+	attrs map[string]string
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(attrs map[string]string) { s.attrs = attrs }
+func (s *testSpan) RecordError(err error)                 { s.err = err }
+func (s *testSpan) End()                                  { s.ended = true }
+
+type testMetrics struct {
+	latencies []string
+	errors    []string
+}
+
+func (m *testMetrics) ObserveLatency(method string, latency time.Duration) {
+	m.latencies = append(m.latencies, method)
+}
+
+func (m *testMetrics) IncError(method, reason string) {
+	m.errors = append(m.errors, method+" "+reason)
+}
+
+func TestTracingAndMetrics(t *testing.T) {
+	tracer, metrics := &testTracer{}, &testMetrics{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(HeaderRequestId, "server-id")
+		if r.URL.Path == "/items/2" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode":900,"errorCode":"NotFound"}`))
+			return
+		}
+		w.Write([]byte(`{"statusCode":800,"returnObj":{}}`))
+	}, WithTracer(tracer), WithMetrics(metrics))
+
+	for _, id := range []string{"1", "2"} {
+		c.R().SetCallInfo(&CallInfo{Service: "Item", Method: "Get", Route: "/items/:id"}, nil).
+			SetPathParams(map[string]string{"id": id}).
+			SetResult(&OpenapiResponse{}).
+			Execute(http.MethodGet, "/items/:id")
+	}
+	if len(tracer.spans) != 2 || tracer.names[0] != "Item.Get" {
+		t.Fatalf("spans %v", tracer.names)
+	}
+	ok, failed := tracer.spans[0], tracer.spans[1]
+	if !ok.ended || ok.err != nil || ok.attrs[AttrHttpMethod] != "GET" || ok.attrs[AttrHttpRoute] != "/items/:id" ||
+		ok.attrs[AttrStatusCode] != "200" || ok.attrs[AttrRequestId] != "server-id" {
+		t.Fatalf("span %+v", ok)
+	}
+	if !failed.ended || failed.err == nil || failed.attrs[AttrStatusCode] != "404" {
+		t.Fatalf("failed span %+v", failed)
+	}
+	if len(metrics.latencies) != 2 || len(metrics.errors) != 1 || metrics.errors[0] != "Item.Get NotFound" {
+		t.Fatalf("metrics %+v", metrics)
+	}
+}